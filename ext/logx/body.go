@@ -0,0 +1,204 @@
+package logx
+
+import (
+	"bytes"
+	"io"
+	"mime"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/pkg/logging"
+)
+
+const defaultBodyMaxSize = 4 << 10 // 4KB
+
+var defaultBodyContentTypes = []string{"application/json", "application/x-www-form-urlencoded", "text/plain"}
+
+type bodyCaptureConfig struct {
+	routes       map[string]struct{} // 为空表示所有路由
+	maxSize      int
+	contentTypes map[string]struct{}
+	redactKeys   []string
+	redactHeader []string
+	redactor     *Redactor
+}
+
+func (cfg *accessLogConfig) bodyConfig() *bodyCaptureConfig {
+	if cfg.body == nil {
+		cfg.body = &bodyCaptureConfig{
+			routes:       make(map[string]struct{}),
+			maxSize:      defaultBodyMaxSize,
+			contentTypes: make(map[string]struct{}),
+			redactKeys:   DefaultRedactKeys,
+			redactHeader: DefaultRedactHeaders,
+		}
+		for _, ct := range defaultBodyContentTypes {
+			cfg.body.contentTypes[ct] = struct{}{}
+		}
+	}
+	return cfg.body
+}
+
+// WithBodyCapture 启用请求/响应 body 及 Header 记录，routes 为空时对所有路由生效
+func WithBodyCapture(routes ...string) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		bc := cfg.bodyConfig()
+		for _, r := range routes {
+			if r != "" {
+				bc.routes[r] = struct{}{}
+			}
+		}
+	}
+}
+
+// WithBodyMaxSize 设置 body 最大记录字节数，超出部分截断，默认 4KB
+func WithBodyMaxSize(n int) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		if n > 0 {
+			cfg.bodyConfig().maxSize = n
+		}
+	}
+}
+
+// WithBodyContentTypes 设置允许记录 body 的 Content-Type 白名单
+func WithBodyContentTypes(types ...string) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		bc := cfg.bodyConfig()
+		bc.contentTypes = make(map[string]struct{}, len(types))
+		for _, t := range types {
+			bc.contentTypes[strings.ToLower(t)] = struct{}{}
+		}
+	}
+}
+
+// WithRedactKeys 设置需脱敏的 JSON/表单字段，默认 password、token、id_card、phone
+func WithRedactKeys(keys ...string) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		cfg.bodyConfig().redactKeys = keys
+	}
+}
+
+// WithRedactHeaders 设置需脱敏的 Header，默认 Authorization、Cookie、Set-Cookie
+func WithRedactHeaders(headers ...string) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		cfg.bodyConfig().redactHeader = headers
+	}
+}
+
+// init 在所有 option 应用后构建脱敏器
+func (bc *bodyCaptureConfig) init() {
+	bc.redactor = NewRedactor(bc.redactKeys, bc.redactHeader)
+}
+
+func (bc *bodyCaptureConfig) enabled(route string) bool {
+	if bc == nil {
+		return false
+	}
+	if len(bc.routes) == 0 {
+		return true
+	}
+	_, ok := bc.routes[route]
+	return ok
+}
+
+func (bc *bodyCaptureConfig) allowContentType(contentType string) bool {
+	mt, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	_, ok := bc.contentTypes[mt]
+	return ok
+}
+
+// bodyCapture 单次请求的 body 记录状态
+type bodyCapture struct {
+	cfg       *bodyCaptureConfig
+	req       []byte
+	reqTrunc  bool
+	reqHeader map[string]string
+	writer    *bodyWriter
+}
+
+// captureRequest 读取请求 body 前 maxSize 字节，并还原 c.Request.Body 供后续 handler 读取
+func (bc *bodyCaptureConfig) captureRequest(c *gin.Context) *bodyCapture {
+	state := &bodyCapture{
+		cfg:       bc,
+		reqHeader: bc.redactor.RedactHeaders(c.Request.Header),
+	}
+
+	if c.Request.Body != nil && bc.allowContentType(c.GetHeader("Content-Type")) {
+		buf, err := io.ReadAll(io.LimitReader(c.Request.Body, int64(bc.maxSize)+1))
+		if err == nil || len(buf) > 0 {
+			c.Request.Body = readCloser{Reader: io.MultiReader(bytes.NewReader(buf), c.Request.Body), Closer: c.Request.Body}
+			if len(buf) > bc.maxSize {
+				buf, state.reqTrunc = buf[:bc.maxSize], true
+			}
+			state.req = buf
+		}
+	}
+
+	state.writer = &bodyWriter{ResponseWriter: c.Writer, maxSize: bc.maxSize}
+	c.Writer = state.writer
+	return state
+}
+
+// apply 写入脱敏后的 body 及 Header 字段
+func (s *bodyCapture) apply(c *gin.Context, e *logging.Event) {
+	e.Interface("req_headers", s.reqHeader)
+	if len(s.req) > 0 {
+		e.Str("req_body", s.redact(c.GetHeader("Content-Type"), s.req, s.reqTrunc))
+	}
+	if ct := c.Writer.Header().Get("Content-Type"); s.writer.buf.Len() > 0 && s.cfg.allowContentType(ct) {
+		e.Str("resp_body", s.redact(ct, s.writer.buf.Bytes(), s.writer.truncated))
+	}
+}
+
+func (s *bodyCapture) redact(contentType string, body []byte, truncated bool) string {
+	mt, _, _ := mime.ParseMediaType(contentType)
+	switch {
+	case strings.HasSuffix(mt, "json"):
+		body = s.cfg.redactor.RedactJSON(body)
+	case mt == "application/x-www-form-urlencoded":
+		body = s.cfg.redactor.RedactForm(body)
+	}
+
+	if truncated {
+		return string(body) + " ...[truncated]"
+	}
+	return string(body)
+}
+
+type readCloser struct {
+	io.Reader
+	io.Closer
+}
+
+// bodyWriter 复制响应 body 前 maxSize 字节
+type bodyWriter struct {
+	gin.ResponseWriter
+	maxSize   int
+	buf       bytes.Buffer
+	truncated bool
+}
+
+func (w *bodyWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *bodyWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+func (w *bodyWriter) capture(b []byte) {
+	remain := w.maxSize - w.buf.Len()
+	if remain <= 0 {
+		w.truncated = w.truncated || len(b) > 0
+		return
+	}
+	if len(b) > remain {
+		b, w.truncated = b[:remain], true
+	}
+	w.buf.Write(b)
+}
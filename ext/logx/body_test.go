@@ -0,0 +1,75 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBodyCapture(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logging.Init(logging.WithOutput(&buf))
+	defer logging.Init()
+
+	engine := gin.New()
+	engine.Use(GinAccessLog(WithBodyCapture("/login"), WithBodyMaxSize(64)))
+	engine.POST("/login", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		c.JSON(http.StatusOK, gin.H{"token": "secret-token", "echo": len(body)})
+	})
+	engine.POST("/other", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	serve := func(path, body string) map[string]any {
+		buf.Reset()
+		req := httptest.NewRequest(http.MethodPost, path, strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("Authorization", "Bearer abc")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		return entry
+	}
+
+	t.Run("Redact", func(t *testing.T) {
+		body := `{"user":"bob","password":"p@ss","profile":{"phone":"13800000000"}}`
+		entry := serve("/login", body)
+
+		reqBody := entry["req_body"].(string)
+		assert.Contains(t, reqBody, `"user":"bob"`)
+		assert.NotContains(t, reqBody, "p@ss")
+		assert.NotContains(t, reqBody, "13800000000")
+
+		assert.NotContains(t, entry["resp_body"], "secret-token")
+		assert.Contains(t, entry["resp_body"], fmt.Sprintf(`"echo":%d`, len(body)))
+		assert.Equal(t, RedactedValue, entry["req_headers"].(map[string]any)["Authorization"])
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		long := `{"password":"p@ss","data":"` + strings.Repeat("x", 100) + `"}`
+		entry := serve("/login", long)
+
+		reqBody := entry["req_body"].(string)
+		assert.NotContains(t, reqBody, "p@ss")
+		assert.True(t, strings.HasSuffix(reqBody, "...[truncated]"))
+		// handler 仍能读取完整 body
+		assert.Contains(t, entry["resp_body"], fmt.Sprintf(`"echo":%d`, len(long)))
+	})
+
+	t.Run("RouteNotEnabled", func(t *testing.T) {
+		entry := serve("/other", `{"a":1}`)
+		assert.NotContains(t, entry, "req_body")
+	})
+}
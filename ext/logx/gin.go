@@ -1,6 +1,11 @@
 package logx
 
 import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net/http"
+	"strings"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -12,17 +17,44 @@ const (
 	// HeaderRequestID 请求中携带的 requestID Header
 	HeaderRequestID = "X-Request-ID"
 	ctxKeyRequestID = "requestId"
+	// CtxKeyUserID 鉴权中间件写入 gin.Context 的用户 ID key
+	CtxKeyUserID = "userId"
+
+	defaultRequestIDMaxLen = 64
+	// defaultNPlusOneThreshold 同一 SQL 模板在单个请求内执行次数达到该值时告警
+	defaultNPlusOneThreshold = 10
 )
 
+type ctxRequestIDKey struct{}
+
 type accessLogConfig struct {
-	skipPaths map[string]struct{}
+	skipPaths       map[string]struct{}
+	requestIDHeader string
+	requestIDMaxLen int
+
+	userID          func(c *gin.Context) string
+	sampleRates     map[string]float64 // 路由模板 -> 采样率
+	slowThreshold   time.Duration
+	alwaysLogErrors bool
+
+	nPlusOneThreshold int
+
+	body     *bodyCaptureConfig // 为 nil 表示不记录 body
+	redactor *Redactor          // path 中 query 参数脱敏
 }
 
 type AccessLogOption func(*accessLogConfig)
 
 func defaultConfig() *accessLogConfig {
 	return &accessLogConfig{
-		skipPaths: make(map[string]struct{}),
+		skipPaths:       make(map[string]struct{}),
+		requestIDHeader: HeaderRequestID,
+		requestIDMaxLen: defaultRequestIDMaxLen,
+		userID:          userIDFromGin,
+		sampleRates:     make(map[string]float64),
+		alwaysLogErrors: true,
+
+		nPlusOneThreshold: defaultNPlusOneThreshold,
 	}
 }
 
@@ -37,12 +69,82 @@ func WithSkipPaths(paths ...string) AccessLogOption {
 	}
 }
 
+// WithRequestIDHeader 设置读取与回写 requestId 的 Header，默认 X-Request-ID
+func WithRequestIDHeader(name string) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		if name != "" {
+			cfg.requestIDHeader = name
+		}
+	}
+}
+
+// WithRequestIDMaxLen 设置上游 requestId 的最大长度，超长时重新生成，默认 64
+func WithRequestIDMaxLen(n int) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		if n > 0 {
+			cfg.requestIDMaxLen = n
+		}
+	}
+}
+
+// WithUserID 自定义获取已认证用户 ID 的方式，默认读取 gin.Context 中的 userId
+func WithUserID(fn func(c *gin.Context) string) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		if fn != nil {
+			cfg.userID = fn
+		}
+	}
+}
+
+// WithSampleRate 设置指定路由的采样率（0~1），用于高 QPS 接口
+func WithSampleRate(route string, rate float64) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		cfg.sampleRates[route] = min(max(rate, 0), 1)
+	}
+}
+
+// WithSlowThreshold 耗时超过阈值的请求总是记录（warn），不受采样影响
+func WithSlowThreshold(d time.Duration) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		cfg.slowThreshold = d
+	}
+}
+
+// WithAlwaysLogErrors 是否总是记录 4xx/5xx 及带错误的请求，不受采样影响，默认 true
+func WithAlwaysLogErrors(always bool) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		cfg.alwaysLogErrors = always
+	}
+}
+
+// WithNPlusOneThreshold 同一 SQL 模板在单个请求内执行达到 n 次时以 warn 记录，<=0 关闭
+// 需 GormLogger 通过 db.Use 注册为插件才能拿到准确的模板，见 NewGormLogger
+func WithNPlusOneThreshold(n int) AccessLogOption {
+	return func(cfg *accessLogConfig) {
+		cfg.nPlusOneThreshold = n
+	}
+}
+
+// redactURI 屏蔽 query 中的敏感参数，如 ?token=xxx
+func (cfg *accessLogConfig) redactURI(uri string) string {
+	path, query, ok := strings.Cut(uri, "?")
+	if !ok {
+		return uri
+	}
+	return path + "?" + string(cfg.redactor.RedactForm([]byte(query)))
+}
+
 // GinAccessLog Gin 请求访问日志中间件（支持跳过路径）
 func GinAccessLog(opts ...AccessLogOption) gin.HandlerFunc {
 	cfg := defaultConfig()
 	for _, opt := range opts {
 		opt(cfg)
 	}
+	cfg.redactor = NewRedactor(DefaultRedactKeys, nil)
+	if cfg.body != nil {
+		cfg.body.init()
+		cfg.redactor = cfg.body.redactor
+	}
 
 	return func(c *gin.Context) {
 		path := c.FullPath()
@@ -54,35 +156,124 @@ func GinAccessLog(opts ...AccessLogOption) gin.HandlerFunc {
 		start := time.Now()
 
 		// 解析或生成 requestId
-		requestID := resolveRequestID(c)
+		requestID := cfg.resolveRequestID(c)
 
-		// 注入 gin context
+		// 注入 gin context，并回写到响应头
 		c.Set(ctxKeyRequestID, requestID)
+		c.Header(cfg.requestIDHeader, requestID)
 
 		// 注入 context 中
-		ctx := logging.WithFields(c.Request.Context(), logging.Str(ctxKeyRequestID, requestID))
+		ctx := WithRequestID(c.Request.Context(), requestID)
+		ctx = logging.WithFields(ctx, logging.Str(ctxKeyRequestID, requestID))
+		ctx, stats := WithQueryStats(ctx)
 
 		c.Request = c.Request.WithContext(ctx)
+
+		var capture *bodyCapture
+		if cfg.body.enabled(path) {
+			capture = cfg.body.captureRequest(c)
+		}
+
 		c.Next()
 
-		logging.L().Info(ctx).
-			Int("status", c.Writer.Status()).
-			Int64("latency_ms", time.Since(start).Milliseconds()).
+		latency := time.Since(start)
+		status := c.Writer.Status()
+		slow := cfg.slowThreshold > 0 && latency > cfg.slowThreshold
+		failed := status >= http.StatusBadRequest || len(c.Errors) > 0
+		repeatedSQL, repeatedTimes := stats.Repeated(cfg.nPlusOneThreshold)
+		suspicious := slow || repeatedTimes > 0
+		if !cfg.sampled(path, suspicious, failed) {
+			return
+		}
+
+		e := accessLogEvent(ctx, status, suspicious).
+			Int("status", status).
+			Int64("latency_ms", latency.Milliseconds()).
 			Str("method", c.Request.Method).
-			Str("path", c.Request.RequestURI).
-			Msg("gin access")
+			Str("path", cfg.redactURI(c.Request.RequestURI)).
+			Str("route", path).
+			Str("client_ip", c.ClientIP()).
+			Str("user_agent", c.Request.UserAgent()).
+			Str("referer", c.Request.Referer()).
+			Int("resp_size", max(c.Writer.Size(), 0))
+		if uid := cfg.userID(c); uid != "" {
+			e.Str("user_id", uid)
+		}
+		if len(c.Errors) > 0 {
+			e.Strs("errors", c.Errors.Errors())
+		}
+		if slow {
+			e.Bool("slow", true)
+		}
+		if n := stats.Count(); n > 0 {
+			e.Int("db_query_count", n).Int64("db_time_ms", stats.Duration().Milliseconds())
+		}
+		if repeatedTimes > 0 {
+			e.Str("n_plus_one_sql", repeatedSQL).Int("n_plus_one_times", repeatedTimes)
+		}
+		if capture != nil {
+			capture.apply(c, e)
+		}
+		e.Msg("gin access")
+	}
+}
+
+// accessLogEvent 按状态码选择日志级别：5xx error，4xx 及慢请求、疑似 N+1 warn
+func accessLogEvent(ctx context.Context, status int, suspicious bool) *logging.Event {
+	switch {
+	case status >= http.StatusInternalServerError:
+		return logging.L().Error(ctx)
+	case status >= http.StatusBadRequest || suspicious:
+		return logging.L().Warn(ctx)
+	default:
+		return logging.L().Info(ctx)
 	}
 }
 
-func resolveRequestID(c *gin.Context) string {
+// sampled 判断是否记录本次请求，慢请求（含疑似 N+1）与错误请求可跳过采样
+func (cfg *accessLogConfig) sampled(route string, suspicious, failed bool) bool {
+	if suspicious || (failed && cfg.alwaysLogErrors) {
+		return true
+	}
+	rate, ok := cfg.sampleRates[route]
+	if !ok {
+		return true
+	}
+	return rand.Float64() < rate
+}
+
+func userIDFromGin(c *gin.Context) string {
+	if v, ok := c.Get(CtxKeyUserID); ok && v != nil {
+		return fmt.Sprint(v)
+	}
+	return ""
+}
+
+func (cfg *accessLogConfig) resolveRequestID(c *gin.Context) string {
 	if c.Request != nil {
-		if logID := c.GetHeader(HeaderRequestID); logID != "" {
+		if logID := c.GetHeader(cfg.requestIDHeader); validRequestID(logID, cfg.requestIDMaxLen) {
 			return logID
 		}
 	}
 	return GenerateRequestID()
 }
 
+// validRequestID 校验上游 requestId，仅允许字母、数字及 -_.: 防止日志注入
+func validRequestID(id string, maxLen int) bool {
+	if id == "" || len(id) > maxLen {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		switch ch := id[i]; {
+		case ch >= 'a' && ch <= 'z', ch >= 'A' && ch <= 'Z', ch >= '0' && ch <= '9':
+		case ch == '-', ch == '_', ch == '.', ch == ':':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 func GenerateRequestID() string {
 	return ulid.Make().String()
 }
@@ -96,3 +287,17 @@ func GetRequestID(c *gin.Context) string {
 	}
 	return ""
 }
+
+// WithRequestID 将 requestId 注入 context
+func WithRequestID(ctx context.Context, requestID string) context.Context {
+	return context.WithValue(ctx, ctxRequestIDKey{}, requestID)
+}
+
+// RequestIDFrom 从 context 获取 requestId
+func RequestIDFrom(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	id, _ := ctx.Value(ctxRequestIDKey{}).(string)
+	return id
+}
@@ -0,0 +1,119 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRequestID(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	// 下游服务：记录收到的 Header
+	var received http.Header
+	downstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		received = r.Header.Clone()
+	}))
+	defer downstream.Close()
+
+	client := &http.Client{Transport: NewTransport(nil, WithTransportField("tenant", "X-Tenant"))}
+
+	engine := gin.New()
+	engine.Use(GinAccessLog(WithRequestIDHeader("X-Trace-Id")))
+	engine.GET("/call", func(c *gin.Context) {
+		ctx := logging.WithFields(c.Request.Context(), logging.Str("tenant", "t1"))
+		req, _ := http.NewRequestWithContext(ctx, http.MethodGet, downstream.URL, nil)
+		resp, err := client.Do(req)
+		require.NoError(t, err)
+		_ = resp.Body.Close()
+		c.Status(http.StatusOK)
+	})
+
+	serve := func(requestID string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/call", nil)
+		if requestID != "" {
+			req.Header.Set("X-Trace-Id", requestID)
+		}
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, req)
+		return w
+	}
+
+	t.Run("Propagate", func(t *testing.T) {
+		w := serve("abc-123")
+		assert.Equal(t, "abc-123", w.Header().Get("X-Trace-Id"))
+		assert.Equal(t, "abc-123", received.Get(HeaderRequestID))
+		assert.Equal(t, "t1", received.Get("X-Tenant"))
+	})
+
+	t.Run("RejectInvalid", func(t *testing.T) {
+		for _, id := range []string{"bad\" id", "a\\nb", strings.Repeat("x", 65)} {
+			w := serve(id)
+			got := w.Header().Get("X-Trace-Id")
+			assert.NotEqual(t, id, got)
+			assert.Len(t, got, 26) // ulid
+		}
+	})
+}
+
+func TestGinAccessLogFields(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logging.Init(logging.WithOutput(&buf))
+	defer logging.Init()
+
+	engine := gin.New()
+	engine.Use(GinAccessLog(WithSampleRate("/hot", 0)))
+	engine.GET("/hot", func(c *gin.Context) { c.Status(http.StatusOK) })
+	engine.GET("/fail", func(c *gin.Context) {
+		c.Set(CtxKeyUserID, 42)
+		_ = c.Error(errors.New("boom"))
+		c.String(http.StatusInternalServerError, "oops")
+	})
+
+	serve := func(path string) {
+		req := httptest.NewRequest(http.MethodGet, path, nil)
+		req.Header.Set("User-Agent", "test-agent")
+		engine.ServeHTTP(httptest.NewRecorder(), req)
+	}
+
+	t.Run("Sampled", func(t *testing.T) {
+		buf.Reset()
+		serve("/hot")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("ErrorAlwaysLogged", func(t *testing.T) {
+		buf.Reset()
+		serve("/fail")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "error", entry["level"])
+		assert.Equal(t, "/fail", entry["route"])
+		assert.Equal(t, "test-agent", entry["user_agent"])
+		assert.Equal(t, "42", entry["user_id"])
+		assert.Equal(t, float64(4), entry["resp_size"])
+		assert.Equal(t, []any{"boom"}, entry["errors"])
+	})
+
+	t.Run("RedactQuery", func(t *testing.T) {
+		buf.Reset()
+		serve("/fail?token=secret&Password=p%40ss&page=2")
+
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &entry))
+		assert.Equal(t, "/fail?token=******&Password=******&page=2", entry["path"])
+		assert.NotContains(t, buf.String(), "secret")
+	})
+}
@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"regexp"
+	"strings"
 	"time"
 
 	"github.com/lpphub/goweb/pkg/logging"
@@ -13,20 +15,122 @@ import (
 
 // GormLogger 自定义GORM日志记录器
 type GormLogger struct {
-	logger        logging.Logger
+	logger        *callerLogger
 	logLevel      logger.LogLevel
 	slowThreshold time.Duration
 	sqlMaxLen     int
+	successLevel  logging.Level
+	slowLevel     logging.Level
+	errorLevel    logging.Level
+	ignoreTables  map[string]struct{}
+
+	resolver   LoggerResolver
+	callerSkip int
+
+	withParams bool      // SQL 模板与参数分离记录
+	redactor   *Redactor // 参数脱敏规则（按列名）
+}
+
+type GormOption func(*GormLogger)
+
+// WithSQLParams 分开记录 SQL 模板与参数（参数按列名脱敏），需通过 db.Use 注册以采集参数；
+// 未注册时只记录 SQL 模板，不输出参数
+func WithSQLParams() GormOption {
+	return func(l *GormLogger) {
+		l.withParams = true
+	}
+}
+
+// WithSQLRedactColumns 设置需脱敏的列名，默认同 DefaultRedactKeys
+func WithSQLRedactColumns(columns ...string) GormOption {
+	return func(l *GormLogger) {
+		l.redactor = NewRedactor(columns, nil)
+	}
+}
+
+// WithSQLSlowThreshold 设置慢查询阈值，默认 1s，<=0 关闭
+func WithSQLSlowThreshold(d time.Duration) GormOption {
+	return func(l *GormLogger) {
+		l.slowThreshold = d
+	}
+}
+
+// WithSQLMaxLen 设置 SQL 最大记录长度，默认 1024
+func WithSQLMaxLen(n int) GormOption {
+	return func(l *GormLogger) {
+		if n > 0 {
+			l.sqlMaxLen = n
+		}
+	}
+}
+
+// WithSQLSuccessLevel 设置成功 SQL 的日志级别，默认 info，可设为 logging.DebugLevel 或 logging.Disabled
+func WithSQLSuccessLevel(level logging.Level) GormOption {
+	return func(l *GormLogger) {
+		l.successLevel = level
+	}
+}
+
+// WithSQLSlowLevel 设置慢查询的日志级别，默认 warn
+func WithSQLSlowLevel(level logging.Level) GormOption {
+	return func(l *GormLogger) {
+		l.slowLevel = level
+	}
+}
+
+// WithSQLErrorLevel 设置 SQL 错误的日志级别，默认 error
+func WithSQLErrorLevel(level logging.Level) GormOption {
+	return func(l *GormLogger) {
+		l.errorLevel = level
+	}
+}
+
+// WithSQLIgnoreTables 忽略指定表的 SQL 日志（错误仍会记录）
+func WithSQLIgnoreTables(tables ...string) GormOption {
+	return func(l *GormLogger) {
+		for _, t := range tables {
+			if t != "" {
+				l.ignoreTables[strings.ToLower(t)] = struct{}{}
+			}
+		}
+	}
+}
+
+// WithSQLLogger 设置 Logger 获取方式，每次输出日志时调用，默认 logging.Named("gorm")
+func WithSQLLogger(resolver LoggerResolver) GormOption {
+	return func(l *GormLogger) {
+		l.resolver = resolver
+	}
+}
+
+// WithSQLCallerSkip 设置 caller 跳过的栈帧数，默认 5（指向业务代码调用处）
+func WithSQLCallerSkip(skip int) GormOption {
+	return func(l *GormLogger) {
+		l.callerSkip = skip
+	}
 }
 
 // NewGormLogger 创建新的GORM日志记录器
-func NewGormLogger() logger.Interface {
-	return &GormLogger{
+// 建议同时通过 db.Use(l) 注册为插件，以按 SQL 模板统计请求内的查询（N+1 检测）；
+// 未注册时只能拿到插值后的 SQL，按去除字面量后的近似模板聚合
+func NewGormLogger(opts ...GormOption) *GormLogger {
+	l := &GormLogger{
 		logLevel:      logger.Info,
-		logger:        logging.L().WithCaller(5),
 		slowThreshold: 1000 * time.Millisecond,
 		sqlMaxLen:     1024,
+		successLevel:  logging.InfoLevel,
+		slowLevel:     logging.WarnLevel,
+		errorLevel:    logging.ErrorLevel,
+		ignoreTables:  make(map[string]struct{}),
+		callerSkip:    5,
+		resolver:      NamedResolver("gorm"),
+		redactor:      NewRedactor(DefaultRedactKeys, nil),
 	}
+	for _, opt := range opts {
+		opt(l)
+	}
+	l.logger = newCallerLogger(l.resolver, l.callerSkip)
+	return l
 }
 
 // LogMode 设置日志等级
@@ -41,7 +145,7 @@ func (l *GormLogger) Info(ctx context.Context, msg string, data ...interface{})
 		if len(data) > 0 {
 			msg = fmt.Sprintf(msg, data...)
 		}
-		l.log(ctx, logger.Info, msg, nil)
+		l.log(ctx, logger.Info, logging.InfoLevel, msg, nil)
 	}
 }
 
@@ -51,7 +155,7 @@ func (l *GormLogger) Warn(ctx context.Context, msg string, data ...interface{})
 		if len(data) > 0 {
 			msg = fmt.Sprintf(msg, data...)
 		}
-		l.log(ctx, logger.Warn, msg, nil)
+		l.log(ctx, logger.Warn, logging.WarnLevel, msg, nil)
 	}
 }
 
@@ -61,18 +165,75 @@ func (l *GormLogger) Error(ctx context.Context, msg string, data ...interface{})
 		if len(data) > 0 {
 			msg = fmt.Sprintf(msg, data...)
 		}
-		l.log(ctx, logger.Error, msg, nil)
+		l.log(ctx, logger.Error, logging.ErrorLevel, msg, nil)
 	}
 }
 
+// ParamsFilter 实现 gorm.ParamsFilter，参数分离模式下 Trace 只拿到 SQL 模板
+func (l *GormLogger) ParamsFilter(_ context.Context, sql string, params ...interface{}) (string, []interface{}) {
+	if l.withParams {
+		return sql, nil
+	}
+	return sql, params
+}
+
 // Trace 记录 SQL 执行追踪
 func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (string, int64), err error) {
-	if l.logLevel <= logger.Silent {
+	elapsed := time.Since(begin)
+	stats := QueryStatsFrom(ctx)
+	if l.logLevel <= logger.Silent && stats == nil {
 		return
 	}
 
-	elapsed := time.Since(begin)
 	sql, rows := fc()
+	stmt, hasStmt := ctx.Value(ctxSQLStmtKey{}).(sqlStmt)
+
+	// 请求级 SQL 统计（按模板聚合），与日志等级无关
+	if stats != nil {
+		key := stmt.sql
+		if !hasStmt {
+			key = sqlTemplate(sql)
+		}
+		stats.record(key, elapsed)
+	}
+
+	if l.logLevel <= logger.Silent {
+		return
+	}
+
+	failed := err != nil && !errors.Is(err, gorm.ErrRecordNotFound)
+	if !failed && len(l.ignoreTables) > 0 {
+		table := stmt.table
+		if !hasStmt {
+			table = sqlTable(sql)
+		}
+		if _, ok := l.ignoreTables[strings.ToLower(table)]; ok {
+			return
+		}
+	}
+
+	var (
+		level      logger.LogLevel
+		eventLevel logging.Level
+		msg        string
+	)
+	switch {
+	case failed:
+		level, eventLevel, msg = logger.Error, l.errorLevel, fmt.Sprintf("query error: %s", err.Error())
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		level, eventLevel, msg = logger.Warn, l.slowLevel, "slow query"
+	default:
+		level, eventLevel, msg = logger.Info, l.successLevel, "query success"
+	}
+
+	// 不会输出时跳过字段构建（同时遵循 logging 当前级别）
+	if !l.enabled(level, eventLevel) {
+		return
+	}
+
+	if l.withParams && hasStmt {
+		sql = stmt.sql
+	}
 
 	// 截断超长 SQL
 	if len(sql) > l.sqlMaxLen {
@@ -84,29 +245,207 @@ func (l *GormLogger) Trace(ctx context.Context, begin time.Time, fc func() (stri
 		"rows":        rows,
 		"duration_ms": elapsed.Milliseconds(),
 	}
+	if l.withParams && hasStmt && len(stmt.vars) > 0 {
+		fields["params"] = l.redactParams(stmt.sql, stmt.vars)
+	}
 
-	switch {
-	case err != nil && !errors.Is(err, gorm.ErrRecordNotFound):
-		l.log(ctx, logger.Error, fmt.Sprintf("query error: %s", err.Error()), fields)
-	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
-		l.log(ctx, logger.Warn, "slow query", fields)
-	default:
-		l.log(ctx, logger.Info, "query success", fields)
+	l.log(ctx, level, eventLevel, msg, fields)
+}
+
+// enabled gorm 日志等级（Silent=1 < Error < Warn < Info=4）与 logging 级别均允许时才输出
+func (l *GormLogger) enabled(level logger.LogLevel, eventLevel logging.Level) bool {
+	if l.logLevel <= logger.Silent || level > l.logLevel {
+		return false
 	}
+	return l.logger.get().Enabled(eventLevel)
 }
 
-// log 通用日志方法
-func (l *GormLogger) log(ctx context.Context, level logger.LogLevel, msg string, fields map[string]interface{}) {
-	if level < l.logLevel {
+// log 通用日志方法，level 为 gorm 日志等级，eventLevel 为实际输出级别
+func (l *GormLogger) log(ctx context.Context, level logger.LogLevel, eventLevel logging.Level, msg string, fields map[string]interface{}) {
+	if l.logLevel <= logger.Silent || level > l.logLevel {
 		return
 	}
 
-	switch level {
-	case logger.Warn:
-		l.logger.Warn(ctx).Fields(fields).Msg(msg)
-	case logger.Error:
-		l.logger.Error(ctx).Fields(fields).Msg(msg)
-	default:
-		l.logger.Info(ctx).Fields(fields).Msg(msg)
+	l.logger.get().Log(ctx, eventLevel).Fields(fields).Msg(msg)
+}
+
+type ctxSQLStmtKey struct{}
+
+// sqlStmt 由回调采集的 SQL 模板、参数与表名
+type sqlStmt struct {
+	sql   string
+	vars  []interface{}
+	table string
+}
+
+// Name 实现 gorm.Plugin
+func (l *GormLogger) Name() string {
+	return "logx:logger"
+}
+
+// Initialize 实现 gorm.Plugin：在各类操作最后采集 SQL 模板与参数，供 Trace 分离记录及请求级统计
+func (l *GormLogger) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	registers := map[string]func(name string, fn func(*gorm.DB)) error{
+		"create": cb.Create().After("*").Register,
+		"query":  cb.Query().After("*").Register,
+		"update": cb.Update().After("*").Register,
+		"delete": cb.Delete().After("*").Register,
+		"row":    cb.Row().After("*").Register,
+		"raw":    cb.Raw().After("*").Register,
+	}
+	for name, register := range registers {
+		if err := register("logx:capture_sql_"+name, captureSQL); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func captureSQL(db *gorm.DB) {
+	stmt := db.Statement
+	if stmt == nil || stmt.Context == nil || stmt.SQL.Len() == 0 {
+		return
+	}
+	stmt.Context = context.WithValue(stmt.Context, ctxSQLStmtKey{}, sqlStmt{
+		sql:   stmt.SQL.String(),
+		vars:  stmt.Vars,
+		table: stmt.Table,
+	})
+}
+
+var (
+	// 占位符前的比较表达式，如 `password` = ?、id IN (?,?
+	sqlColumnPattern = regexp.MustCompile("(?i)([\\w.`\"]+)\\s*(?:=|<>|!=|>=|<=|>|<|\\s+LIKE|\\s+IN)\\s*(?:\\(\\s*(?:\\?\\s*,\\s*)*)?$")
+	// 未注册插件时从 SQL 中解析表名
+	sqlTablePattern = regexp.MustCompile("(?i)\\b(?:FROM|INTO|UPDATE|JOIN)\\s+[`\"]?([\\w.]+)")
+	// INSERT INTO t (a,b) VALUES
+	sqlInsertPattern = regexp.MustCompile("(?is)^\\s*INSERT\\s+INTO\\s+\\S+\\s*\\(([^)]*)\\)\\s*VALUES")
+)
+
+// redactParams 按占位符对应的列名脱敏参数，无法对应时全部脱敏以免泄露
+func (l *GormLogger) redactParams(sql string, vars []interface{}) []interface{} {
+	columns := sqlParamColumns(sql)
+	out := make([]interface{}, len(vars))
+	for i, v := range vars {
+		switch {
+		case len(columns) != len(vars):
+			out[i] = RedactedValue
+		case l.redactor.IsSensitiveKey(columns[i]):
+			out[i] = RedactedValue
+		default:
+			if b, ok := v.([]byte); ok {
+				v = fmt.Sprintf("<binary %d bytes>", len(b))
+			}
+			out[i] = v
+		}
+	}
+	return out
+}
+
+// sqlParamColumns 解析每个 ? 占位符对应的列名，无法识别时为空串
+func sqlParamColumns(sql string) []string {
+	var insertCols []string
+	valuesAt := -1
+	if m := sqlInsertPattern.FindStringSubmatchIndex(sql); m != nil {
+		for _, c := range strings.Split(sql[m[2]:m[3]], ",") {
+			insertCols = append(insertCols, normalizeColumn(c))
+		}
+		valuesAt = m[1]
+	}
+
+	var (
+		columns []string
+		quote   byte
+	)
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"' || ch == '`':
+			quote = ch
+		case ch == '?':
+			if valuesAt >= 0 && i > valuesAt && len(insertCols) > 0 {
+				columns = append(columns, insertCols[len(columns)%len(insertCols)])
+				continue
+			}
+			prefix := sql[max(0, i-128):i]
+			col := ""
+			if m := sqlColumnPattern.FindStringSubmatch(prefix); m != nil {
+				col = normalizeColumn(m[1])
+			}
+			columns = append(columns, col)
+		}
+	}
+	return columns
+}
+
+// sqlTable 解析 SQL 中的首个表名
+func sqlTable(sql string) string {
+	if m := sqlTablePattern.FindStringSubmatch(sql); m != nil {
+		return normalizeColumn(m[1])
+	}
+	return ""
+}
+
+// sqlTemplate 将插值后 SQL 中的字符串与数字字面量替换为 ?，未注册插件时用于聚合统计
+func sqlTemplate(sql string) string {
+	var b strings.Builder
+	b.Grow(len(sql))
+	for i := 0; i < len(sql); i++ {
+		ch := sql[i]
+		switch {
+		case ch == '\'':
+			for i++; i < len(sql); i++ {
+				if sql[i] == '\\' {
+					i++
+					continue
+				}
+				if sql[i] == '\'' {
+					if i+1 < len(sql) && sql[i+1] == '\'' { // '' 转义
+						i++
+						continue
+					}
+					break
+				}
+			}
+			b.WriteByte('?')
+		case ch == '`' || ch == '"': // 标识符原样保留
+			j := strings.IndexByte(sql[i+1:], ch)
+			if j < 0 {
+				b.WriteString(sql[i:])
+				return b.String()
+			}
+			b.WriteString(sql[i : i+j+2])
+			i += j + 1
+		case isDigit(ch) && (i == 0 || !isIdentChar(sql[i-1])):
+			for i+1 < len(sql) && (isDigit(sql[i+1]) || sql[i+1] == '.') {
+				i++
+			}
+			b.WriteByte('?')
+		default:
+			b.WriteByte(ch)
+		}
+	}
+	return b.String()
+}
+
+func isDigit(ch byte) bool {
+	return ch >= '0' && ch <= '9'
+}
+
+func isIdentChar(ch byte) bool {
+	return isDigit(ch) || ch == '_' || ch == '$' || (ch|0x20 >= 'a' && ch|0x20 <= 'z')
+}
+
+// normalizeColumn 去除引号与表名前缀
+func normalizeColumn(c string) string {
+	c = strings.TrimSpace(c)
+	if i := strings.LastIndexByte(c, '.'); i >= 0 {
+		c = c[i+1:]
 	}
+	return strings.Trim(c, "`\"")
 }
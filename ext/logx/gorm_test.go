@@ -0,0 +1,136 @@
+package logx
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"gorm.io/gorm/logger"
+)
+
+func TestGormLogger(t *testing.T) {
+	t.Run("ParamColumns", func(t *testing.T) {
+		assert.Equal(t, []string{"name", "password", "age"},
+			sqlParamColumns("INSERT INTO `users` (`name`,`password`,`age`) VALUES (?,?,?)"))
+		assert.Equal(t, []string{"phone", "id", "id", ""},
+			sqlParamColumns("SELECT * FROM `users` WHERE `users`.`phone` = ? AND id IN (?,?) AND name = 'a?' LIMIT ?"))
+	})
+
+	t.Run("TemplateWithoutPlugin", func(t *testing.T) {
+		assert.Equal(t, "SELECT * FROM `users` WHERE id = ? AND name = ? AND t2.c1 > ? LIMIT ?",
+			sqlTemplate("SELECT * FROM `users` WHERE id = 42 AND name = 'o''b\\'x' AND t2.c1 > 1.5 LIMIT 10"))
+		assert.Equal(t, "SELECT \"col9\" FROM t1", sqlTemplate(`SELECT "col9" FROM t1`))
+
+		// 未注册插件（ctx 中无模板）时按去除字面量后的 SQL 聚合
+		l := NewGormLogger()
+		l.LogMode(logger.Silent)
+		ctx, stats := WithQueryStats(context.Background())
+		for i := range 3 {
+			l.Trace(ctx, time.Now(), func() (string, int64) {
+				return fmt.Sprintf("SELECT * FROM `orders` WHERE `user_id` = %d", i+1), 1
+			}, nil)
+		}
+		tpl, times := stats.Repeated(3)
+		assert.Equal(t, "SELECT * FROM `orders` WHERE `user_id` = ?", tpl)
+		assert.Equal(t, 3, times)
+	})
+
+	t.Run("StructuredParams", func(t *testing.T) {
+		var buf bytes.Buffer
+		logging.Init(logging.WithOutput(&buf))
+		defer logging.Init()
+
+		l := NewGormLogger(WithSQLParams())
+		ctx, stats := WithQueryStats(context.Background())
+		ctx = context.WithValue(ctx, ctxSQLStmtKey{}, sqlStmt{
+			sql:  "SELECT * FROM users WHERE phone = ? AND age > ?",
+			vars: []interface{}{"13800000000", 18},
+		})
+
+		for range 3 {
+			l.Trace(ctx, time.Now(), func() (string, int64) {
+				return "SELECT * FROM users WHERE phone = '13800000000' AND age > 18", 1
+			}, nil)
+		}
+
+		line, _, _ := bytes.Cut(buf.Bytes(), []byte("\n"))
+		var entry map[string]any
+		require.NoError(t, json.Unmarshal(line, &entry))
+		assert.Equal(t, "SELECT * FROM users WHERE phone = ? AND age > ?", entry["sql"])
+		assert.Equal(t, []any{RedactedValue, float64(18)}, entry["params"])
+		assert.NotContains(t, buf.String(), "13800000000")
+
+		assert.Equal(t, 3, stats.Count())
+		tpl, times := stats.Repeated(3)
+		assert.Equal(t, "SELECT * FROM users WHERE phone = ? AND age > ?", tpl)
+		assert.Equal(t, 3, times)
+	})
+
+	t.Run("ResolverAndSuccessLevel", func(t *testing.T) {
+		l := NewGormLogger(WithSQLIgnoreTables("jobs"))
+		quiet := NewGormLogger(WithSQLSuccessLevel(logging.Disabled))
+
+		// 创建 logger 之后再 Init，输出仍应生效
+		var buf bytes.Buffer
+		logging.Init(logging.WithOutput(&buf))
+		defer logging.Init()
+
+		trace := func(l *GormLogger, sql string) {
+			l.Trace(context.Background(), time.Now(), func() (string, int64) { return sql, 1 }, nil)
+		}
+
+		trace(l, "SELECT * FROM users")
+		assert.Contains(t, buf.String(), "query success")
+
+		buf.Reset()
+		trace(l, "SELECT * FROM `jobs` WHERE id = 1")
+		trace(quiet, "SELECT * FROM users")
+		assert.Empty(t, buf.String())
+	})
+
+	t.Run("LevelMapping", func(t *testing.T) {
+		var buf bytes.Buffer
+		defer logging.ReplaceGlobal(logging.NewLogger(logging.WithOutput(&buf)))()
+
+		l := NewGormLogger(WithSQLSlowThreshold(time.Hour))
+		l.LogMode(logger.Warn)
+
+		trace := func(err error) {
+			l.Trace(context.Background(), time.Now(), func() (string, int64) { return "SELECT 1", 1 }, err)
+		}
+
+		trace(nil)
+		assert.Empty(t, buf.String(), "Warn 模式下不应输出成功日志")
+
+		trace(errors.New("bad conn"))
+		assert.Contains(t, buf.String(), "query error: bad conn")
+
+		// 运行时通过 gorm 组件的级别控制器调整，仅影响 gorm 输出
+		l.LogMode(logger.Info)
+		levels := logging.Named("gorm").Levels()
+		levels.SetLevelFor("gorm", logging.ErrorLevel)
+
+		buf.Reset()
+		trace(nil)
+		assert.Empty(t, buf.String(), "gorm 级别为 error 时不应输出成功日志")
+		trace(errors.New("bad conn"))
+		assert.Contains(t, buf.String(), "query error: bad conn")
+
+		buf.Reset()
+		levels.SetLevelFor("gorm", logging.Disabled)
+		trace(errors.New("bad conn"))
+		assert.Empty(t, buf.String())
+		assert.True(t, logging.L().Enabled(logging.InfoLevel), "全局级别不受影响")
+
+		levels.ResetLevelFor("gorm")
+		trace(nil)
+		assert.Contains(t, buf.String(), "query success")
+	})
+}
@@ -0,0 +1,110 @@
+package logx
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"runtime/debug"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/base"
+	"github.com/lpphub/goweb/pkg/logging"
+)
+
+// ErrInternal panic 时返回给客户端的默认错误，不暴露 panic 细节
+var ErrInternal = base.NewErrorWithStatus(-1, "internal server error", http.StatusInternalServerError)
+
+// PanicCounter panic 计数器，*monitor.Metrics 实现了该接口
+type PanicCounter interface {
+	IncPanic(route string)
+}
+
+type recoveryConfig struct {
+	counter PanicCounter
+	err     error
+}
+
+type RecoveryOption func(*recoveryConfig)
+
+// WithPanicCounter 设置 panic 计数器
+func WithPanicCounter(counter PanicCounter) RecoveryOption {
+	return func(cfg *recoveryConfig) {
+		cfg.counter = counter
+	}
+}
+
+// WithRecoveryError 设置 panic 时响应的错误，默认 ErrInternal
+func WithRecoveryError(err error) RecoveryOption {
+	return func(cfg *recoveryConfig) {
+		if err != nil {
+			cfg.err = err
+		}
+	}
+}
+
+// GinRecovery 捕获 handler panic：通过 logging 记录 panic 与堆栈，并以 base.Result 格式响应
+func GinRecovery(opts ...RecoveryOption) gin.HandlerFunc {
+	cfg := &recoveryConfig{err: ErrInternal}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return func(c *gin.Context) {
+		defer func() {
+			r := recover()
+			if r == nil {
+				return
+			}
+
+			ctx := c.Request.Context()
+			brokenPipe := isBrokenPipe(r)
+
+			logging.L().Error(ctx).
+				Str("panic", fmt.Sprint(r)).
+				Str("stack", string(debug.Stack())).
+				Str("method", c.Request.Method).
+				Str("path", c.Request.URL.Path).
+				Str("route", c.FullPath()).
+				Bool("broken_pipe", brokenPipe).
+				Msg("gin panic recovered")
+
+			if cfg.counter != nil {
+				cfg.counter.IncPanic(c.FullPath())
+			}
+
+			// 连接已断开或响应已写出时无法再返回错误信息
+			if brokenPipe || c.Writer.Written() {
+				if err, ok := r.(error); ok {
+					_ = c.Error(err)
+				}
+				c.Abort()
+				return
+			}
+
+			base.Fail(c, cfg.err)
+		}()
+
+		c.Next()
+	}
+}
+
+// isBrokenPipe 判断是否为客户端断开连接导致的 panic
+func isBrokenPipe(r any) bool {
+	err, ok := r.(error)
+	if !ok {
+		return false
+	}
+	var opErr *net.OpError
+	if !errors.As(err, &opErr) {
+		return false
+	}
+	var sysErr *os.SyscallError
+	if errors.As(opErr, &sysErr) {
+		msg := strings.ToLower(sysErr.Error())
+		return strings.Contains(msg, "broken pipe") || strings.Contains(msg, "connection reset by peer")
+	}
+	return false
+}
@@ -0,0 +1,50 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/base"
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type panicCounter map[string]int
+
+func (p panicCounter) IncPanic(route string) { p[route]++ }
+
+func TestGinRecovery(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logging.Init(logging.WithOutput(&buf))
+	defer logging.Init()
+
+	counter := panicCounter{}
+	engine := gin.New()
+	engine.Use(GinAccessLog(), GinRecovery(WithPanicCounter(counter)))
+	engine.GET("/panic/:id", func(c *gin.Context) { panic("boom") })
+
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/panic/1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, w.Code)
+	var res base.Result
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &res))
+	assert.Equal(t, -1, res.Code)
+	assert.Equal(t, "internal server error", res.Message)
+
+	assert.Equal(t, 1, counter["/panic/:id"])
+
+	var entry map[string]any
+	line, _, _ := bytes.Cut(buf.Bytes(), []byte("\n"))
+	require.NoError(t, json.Unmarshal(line, &entry))
+	assert.Equal(t, "boom", entry["panic"])
+	assert.NotEmpty(t, entry["stack"])
+	assert.NotEmpty(t, entry["requestId"])
+}
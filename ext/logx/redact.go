@@ -0,0 +1,169 @@
+package logx
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// RedactedValue 脱敏后的占位值
+const RedactedValue = "******"
+
+var (
+	// DefaultRedactKeys 默认脱敏的 JSON/表单字段
+	DefaultRedactKeys = []string{"password", "token", "id_card", "phone"}
+	// DefaultRedactHeaders 默认脱敏的 Header
+	DefaultRedactHeaders = []string{"Authorization", "Cookie", "Set-Cookie"}
+)
+
+// Redactor 日志脱敏器：按字段名屏蔽 JSON/表单值与 Header（字段名不区分大小写）
+type Redactor struct {
+	keys    map[string]struct{}
+	headers map[string]struct{}
+	pattern *regexp.Regexp // JSON 解析失败（如被截断）时的兜底匹配
+}
+
+// NewRedactor 创建脱敏器
+func NewRedactor(keys, headers []string) *Redactor {
+	r := &Redactor{
+		keys:    make(map[string]struct{}, len(keys)),
+		headers: make(map[string]struct{}, len(headers)),
+	}
+	quoted := make([]string, 0, len(keys))
+	for _, k := range keys {
+		if k == "" {
+			continue
+		}
+		r.keys[strings.ToLower(k)] = struct{}{}
+		quoted = append(quoted, regexp.QuoteMeta(k))
+	}
+	for _, h := range headers {
+		if h != "" {
+			r.headers[http.CanonicalHeaderKey(h)] = struct{}{}
+		}
+	}
+	if len(quoted) > 0 {
+		// "key": "value" 或 "key": 123
+		r.pattern = regexp.MustCompile(`(?i)("(?:` + strings.Join(quoted, "|") + `)"\s*:\s*)("(?:[^"\\]|\\.)*"?|[^,}\]\s]+)`)
+	}
+	return r
+}
+
+// IsSensitiveKey 判断字段是否需要脱敏
+func (r *Redactor) IsSensitiveKey(key string) bool {
+	_, ok := r.keys[strings.ToLower(key)]
+	return ok
+}
+
+// RedactJSON 屏蔽 JSON 中的敏感字段（支持嵌套对象与数组），其余内容按原文保留（数字精度、字段顺序、转义）
+func (r *Redactor) RedactJSON(body []byte) []byte {
+	if len(r.keys) == 0 || len(body) == 0 {
+		return body
+	}
+	if !json.Valid(body) {
+		return r.pattern.ReplaceAll(body, []byte(`${1}"`+RedactedValue+`"`))
+	}
+
+	var buf bytes.Buffer
+	if err := r.redactRaw(&buf, body); err != nil {
+		return body
+	}
+	return buf.Bytes()
+}
+
+// redactRaw 逐层改写对象与数组，标量值原样写出
+func (r *Redactor) redactRaw(buf *bytes.Buffer, raw []byte) error {
+	raw = bytes.TrimSpace(raw)
+	if len(raw) == 0 || (raw[0] != '{' && raw[0] != '[') {
+		buf.Write(raw)
+		return nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	if _, err := dec.Token(); err != nil { // { 或 [
+		return err
+	}
+	object := raw[0] == '{'
+	buf.WriteByte(raw[0])
+	for n := 0; dec.More(); n++ {
+		if n > 0 {
+			buf.WriteByte(',')
+		}
+		sensitive := false
+		if object {
+			tok, err := dec.Token()
+			if err != nil {
+				return err
+			}
+			key, _ := tok.(string)
+			writeJSONString(buf, key)
+			buf.WriteByte(':')
+			sensitive = r.IsSensitiveKey(key)
+		}
+		var v json.RawMessage
+		if err := dec.Decode(&v); err != nil {
+			return err
+		}
+		if sensitive {
+			writeJSONString(buf, RedactedValue)
+			continue
+		}
+		if err := r.redactRaw(buf, v); err != nil {
+			return err
+		}
+	}
+	if object {
+		buf.WriteByte('}')
+	} else {
+		buf.WriteByte(']')
+	}
+	return nil
+}
+
+// writeJSONString 写出 JSON 字符串，不转义 HTML 字符
+func writeJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode 追加的换行
+}
+
+// RedactForm 屏蔽 x-www-form-urlencoded 中的敏感字段，逐个键值对处理，
+// 其余内容按原文保留，请求体被截断或编码非法时同样生效
+func (r *Redactor) RedactForm(body []byte) []byte {
+	if len(r.keys) == 0 || len(body) == 0 {
+		return body
+	}
+
+	pairs := strings.Split(string(body), "&")
+	for i, pair := range pairs {
+		k, _, ok := strings.Cut(pair, "=")
+		if !ok {
+			continue
+		}
+		key, err := url.QueryUnescape(k)
+		if err != nil {
+			key = k
+		}
+		if r.IsSensitiveKey(key) {
+			pairs[i] = k + "=" + RedactedValue
+		}
+	}
+	return []byte(strings.Join(pairs, "&"))
+}
+
+// RedactHeaders 返回脱敏后的 Header
+func (r *Redactor) RedactHeaders(h http.Header) map[string]string {
+	out := make(map[string]string, len(h))
+	for k, v := range h {
+		if _, ok := r.headers[http.CanonicalHeaderKey(k)]; ok {
+			out[k] = RedactedValue
+			continue
+		}
+		out[k] = strings.Join(v, ", ")
+	}
+	return out
+}
@@ -0,0 +1,36 @@
+package logx
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactJSON(t *testing.T) {
+	r := NewRedactor(DefaultRedactKeys, DefaultRedactHeaders)
+
+	// 数字精度、字段顺序与 HTML 字符保持原样
+	in := `{"z":1234567890123456789,"password":"p<1>","a":{"Token":42,"list":[{"phone":"13800138000"},1.50]},"note":"<b>&"}`
+	want := `{"z":1234567890123456789,"password":"******","a":{"Token":"******","list":[{"phone":"******"},1.50]},"note":"<b>&"}`
+	assert.Equal(t, want, string(r.RedactJSON([]byte(in))))
+
+	// 截断的 JSON 按正则兜底
+	assert.Equal(t, `{"user":"a","password":"******"`, string(r.RedactJSON([]byte(`{"user":"a","password":"secre`))))
+}
+
+func TestRedactForm(t *testing.T) {
+	r := NewRedactor(DefaultRedactKeys, nil)
+
+	assert.Equal(t, "user=a&password=******&x=1", string(r.RedactForm([]byte("user=a&password=secret123&x=1"))))
+
+	// 编码非法或被截断时仍逐个屏蔽，不返回原文
+	for _, body := range []string{
+		"user=a&password=secret123&x=%zz",
+		"user=a&pass%77ord=secret123&x=%2",
+		"user=a&password=secret123&note=%E4%B",
+	} {
+		out := string(r.RedactForm([]byte(body)))
+		assert.NotContains(t, out, "secret123", body)
+		assert.Contains(t, out, "user=a", body)
+	}
+}
@@ -14,18 +14,99 @@ import (
 
 // RedisLogger 自定义Redis客户端日志记录器
 type RedisLogger struct {
-	logger        logging.Logger
-	slowThreshold time.Duration
-	cmdMaxLen     int
+	logger         *callerLogger
+	slowThreshold  time.Duration
+	cmdMaxLen      int
+	successLevel   logging.Level
+	slowLevel      logging.Level
+	errorLevel     logging.Level
+	ignoreCommands map[string]struct{}
+
+	resolver   LoggerResolver
+	callerSkip int
+}
+
+type RedisOption func(*RedisLogger)
+
+// WithRedisSlowThreshold 设置慢命令阈值，默认 100ms，<=0 关闭
+func WithRedisSlowThreshold(d time.Duration) RedisOption {
+	return func(l *RedisLogger) {
+		l.slowThreshold = d
+	}
+}
+
+// WithRedisCmdMaxLen 设置命令最大记录长度，默认 1024
+func WithRedisCmdMaxLen(n int) RedisOption {
+	return func(l *RedisLogger) {
+		if n > 0 {
+			l.cmdMaxLen = n
+		}
+	}
+}
+
+// WithRedisSuccessLevel 设置成功命令的日志级别，默认 info，可设为 logging.DebugLevel 或 logging.Disabled
+func WithRedisSuccessLevel(level logging.Level) RedisOption {
+	return func(l *RedisLogger) {
+		l.successLevel = level
+	}
+}
+
+// WithRedisSlowLevel 设置慢命令的日志级别，默认 warn
+func WithRedisSlowLevel(level logging.Level) RedisOption {
+	return func(l *RedisLogger) {
+		l.slowLevel = level
+	}
+}
+
+// WithRedisErrorLevel 设置命令错误的日志级别，默认 error
+func WithRedisErrorLevel(level logging.Level) RedisOption {
+	return func(l *RedisLogger) {
+		l.errorLevel = level
+	}
+}
+
+// WithRedisIgnoreCommands 忽略指定命令（如 ping）的日志，错误仍会记录
+func WithRedisIgnoreCommands(cmds ...string) RedisOption {
+	return func(l *RedisLogger) {
+		for _, c := range cmds {
+			if c != "" {
+				l.ignoreCommands[strings.ToLower(c)] = struct{}{}
+			}
+		}
+	}
+}
+
+// WithRedisLogger 设置 Logger 获取方式，每次输出日志时调用，默认 logging.Named("redis")
+func WithRedisLogger(resolver LoggerResolver) RedisOption {
+	return func(l *RedisLogger) {
+		l.resolver = resolver
+	}
+}
+
+// WithRedisCallerSkip 设置 caller 跳过的栈帧数，默认 5
+func WithRedisCallerSkip(skip int) RedisOption {
+	return func(l *RedisLogger) {
+		l.callerSkip = skip
+	}
 }
 
 // NewRedisLogger 创建新的Redis日志记录器
-func NewRedisLogger() *RedisLogger {
-	return &RedisLogger{
-		slowThreshold: 100 * time.Millisecond,
-		logger:        logging.L().WithCaller(5),
-		cmdMaxLen:     1024,
+func NewRedisLogger(opts ...RedisOption) *RedisLogger {
+	l := &RedisLogger{
+		slowThreshold:  100 * time.Millisecond,
+		cmdMaxLen:      1024,
+		successLevel:   logging.InfoLevel,
+		slowLevel:      logging.WarnLevel,
+		errorLevel:     logging.ErrorLevel,
+		ignoreCommands: make(map[string]struct{}),
+		callerSkip:     5,
+		resolver:       NamedResolver("redis"),
+	}
+	for _, opt := range opts {
+		opt(l)
 	}
+	l.logger = newCallerLogger(l.resolver, l.callerSkip)
+	return l
 }
 
 func (l *RedisLogger) DialHook(next redis.DialHook) redis.DialHook {
@@ -34,16 +115,21 @@ func (l *RedisLogger) DialHook(next redis.DialHook) redis.DialHook {
 		conn, err := next(ctx, network, addr)
 		elapsed := time.Since(start)
 
+		level, msg := l.successLevel, "redis connected"
+		if err != nil {
+			level, msg = l.errorLevel, "redis connected failed"
+		}
+
+		log := l.logger.get()
+		if !log.Enabled(level) {
+			return conn, err
+		}
+
 		fields := map[string]interface{}{
 			"addr":        addr,
 			"duration_ms": l.fmtDuration(elapsed),
 		}
-
-		if err != nil {
-			l.logger.Error(ctx).Fields(fields).Err(err).Msg("redis connected failed")
-		} else {
-			l.logger.Info(ctx).Fields(fields).Msg("redis connected")
-		}
+		log.Log(ctx, level).Fields(fields).Err(err).Msg(msg)
 		return conn, err
 	}
 }
@@ -55,20 +141,27 @@ func (l *RedisLogger) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
 		err := next(ctx, cmd)
 		elapsed := time.Since(start)
 
+		failed := err != nil && !errors.Is(err, redis.Nil)
+		if !failed && l.ignored(cmd) {
+			return err
+		}
+
+		level, msg := l.eventLevel(failed, elapsed, "redis")
+		log := l.logger.get()
+		if !log.Enabled(level) {
+			return err
+		}
+
 		// 添加字段
 		fields := map[string]interface{}{
 			"cmd":         l.buildCmd(cmd),
 			"duration_ms": l.fmtDuration(elapsed),
 		}
-
-		switch {
-		case err != nil && !errors.Is(err, redis.Nil):
-			l.logger.Error(ctx).Fields(fields).Err(err).Msg("redis error")
-		case l.slowThreshold > 0 && elapsed > l.slowThreshold:
-			l.logger.Warn(ctx).Fields(fields).Msg("redis slow")
-		default:
-			l.logger.Info(ctx).Fields(fields).Msg("redis success")
+		e := log.Log(ctx, level).Fields(fields)
+		if failed {
+			e.Err(err)
 		}
+		e.Msg(msg)
 		return err
 	}
 }
@@ -80,24 +173,63 @@ func (l *RedisLogger) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.
 		err := next(ctx, cmds)
 		elapsed := time.Since(start)
 
+		failed := err != nil && !errors.Is(err, redis.Nil)
+		if !failed && l.allIgnored(cmds) {
+			return err
+		}
+
+		level, msg := l.eventLevel(failed, elapsed, "redis pipeline")
+		log := l.logger.get()
+		if !log.Enabled(level) {
+			return err
+		}
+
 		// 记录管道执行的整体信息
 		fields := map[string]interface{}{
 			"cmd":         l.buildPipelineCmd(cmds),
 			"duration_ms": l.fmtDuration(elapsed),
 		}
-
-		switch {
-		case err != nil && !errors.Is(err, redis.Nil):
-			l.logger.Error(ctx).Fields(fields).Err(err).Msg("redis pipeline error")
-		case l.slowThreshold > 0 && elapsed > l.slowThreshold:
-			l.logger.Warn(ctx).Fields(fields).Msg("redis pipeline slow")
-		default:
-			l.logger.Info(ctx).Fields(fields).Msg("redis pipeline success")
+		e := log.Log(ctx, level).Fields(fields)
+		if failed {
+			e.Err(err)
 		}
+		e.Msg(msg)
 		return err
 	}
 }
 
+// eventLevel 按执行结果选择日志级别与消息
+func (l *RedisLogger) eventLevel(failed bool, elapsed time.Duration, prefix string) (logging.Level, string) {
+	switch {
+	case failed:
+		return l.errorLevel, prefix + " error"
+	case l.slowThreshold > 0 && elapsed > l.slowThreshold:
+		return l.slowLevel, prefix + " slow"
+	default:
+		return l.successLevel, prefix + " success"
+	}
+}
+
+func (l *RedisLogger) ignored(cmd redis.Cmder) bool {
+	if len(l.ignoreCommands) == 0 {
+		return false
+	}
+	_, ok := l.ignoreCommands[strings.ToLower(cmd.Name())]
+	return ok
+}
+
+func (l *RedisLogger) allIgnored(cmds []redis.Cmder) bool {
+	if len(l.ignoreCommands) == 0 || len(cmds) == 0 {
+		return false
+	}
+	for _, cmd := range cmds {
+		if !l.ignored(cmd) {
+			return false
+		}
+	}
+	return true
+}
+
 // buildCmd 构建命令字符串
 func (l *RedisLogger) buildCmd(cmd redis.Cmder) string {
 	args := cmd.Args()
@@ -0,0 +1,31 @@
+package logx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedisLogger(t *testing.T) {
+	var buf bytes.Buffer
+	logging.Init(logging.WithOutput(&buf))
+	defer logging.Init()
+
+	ctx := context.Background()
+	l := NewRedisLogger(WithRedisIgnoreCommands("PING"), WithRedisSuccessLevel(logging.DebugLevel))
+	ok := l.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return nil })
+	fail := l.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error { return errors.New("conn refused") })
+
+	_ = ok(ctx, redis.NewStatusCmd(ctx, "ping"))
+	_ = ok(ctx, redis.NewStringCmd(ctx, "get", "k"))
+	assert.Empty(t, buf.String(), "忽略的命令与 debug 级别的成功日志都不应输出")
+
+	_ = fail(ctx, redis.NewStatusCmd(ctx, "ping"))
+	assert.Contains(t, buf.String(), `"level":"error"`)
+	assert.Contains(t, buf.String(), "conn refused")
+}
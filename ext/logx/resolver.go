@@ -0,0 +1,48 @@
+package logx
+
+import (
+	"sync/atomic"
+
+	"github.com/lpphub/goweb/pkg/logging"
+)
+
+// LoggerResolver 每次输出日志时获取 Logger，使后续 logging.Init 生效
+type LoggerResolver func() *logging.Logger
+
+// NamedResolver 获取 logging.Named(name)，可按组件名单独调整级别
+func NamedResolver(name string) LoggerResolver {
+	return func() *logging.Logger {
+		return logging.Named(name)
+	}
+}
+
+// callerLogger 按 resolver 获取 Logger 并附加 caller，resolver 返回值不变时复用
+type callerLogger struct {
+	resolve LoggerResolver
+	skip    int
+	cached  atomic.Pointer[resolvedLogger]
+}
+
+type resolvedLogger struct {
+	src    *logging.Logger
+	levels *logging.LevelController // Named 句柄在 logging.Init 后指向新 Logger，以级别控制器区分
+	l      logging.Logger
+}
+
+func newCallerLogger(resolve LoggerResolver, skip int) *callerLogger {
+	if resolve == nil {
+		resolve = logging.L
+	}
+	return &callerLogger{resolve: resolve, skip: skip}
+}
+
+func (c *callerLogger) get() logging.Logger {
+	src := c.resolve()
+	levels := src.Levels()
+	if r := c.cached.Load(); r != nil && r.src == src && r.levels == levels {
+		return r.l
+	}
+	l := src.WithCaller(c.skip)
+	c.cached.Store(&resolvedLogger{src: src, levels: levels, l: l})
+	return l
+}
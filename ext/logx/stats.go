@@ -0,0 +1,74 @@
+package logx
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+type ctxQueryStatsKey struct{}
+
+// QueryStats 单个请求内的 SQL 统计，由 GinAccessLog 注入 ctx，GormLogger 累加
+type QueryStats struct {
+	mu        sync.Mutex
+	count     int
+	duration  time.Duration
+	templates map[string]int // SQL 模板 -> 执行次数，用于发现 N+1
+}
+
+// WithQueryStats 在 ctx 中注入新的 SQL 统计
+func WithQueryStats(ctx context.Context) (context.Context, *QueryStats) {
+	stats := &QueryStats{templates: make(map[string]int)}
+	return context.WithValue(ctx, ctxQueryStatsKey{}, stats), stats
+}
+
+// QueryStatsFrom 获取 ctx 中的 SQL 统计，不存在时返回 nil
+func QueryStatsFrom(ctx context.Context) *QueryStats {
+	if ctx == nil {
+		return nil
+	}
+	stats, _ := ctx.Value(ctxQueryStatsKey{}).(*QueryStats)
+	return stats
+}
+
+func (s *QueryStats) record(template string, elapsed time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.count++
+	s.duration += elapsed
+	s.templates[template]++
+}
+
+// Count SQL 执行次数
+func (s *QueryStats) Count() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.count
+}
+
+// Duration SQL 累计耗时
+func (s *QueryStats) Duration() time.Duration {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.duration
+}
+
+// Repeated 返回执行次数最多的 SQL 模板，次数未达到 threshold 时返回空串
+func (s *QueryStats) Repeated(threshold int) (string, int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var (
+		top   string
+		times int
+	)
+	for tpl, n := range s.templates {
+		if n > times {
+			top, times = tpl, n
+		}
+	}
+	if threshold <= 0 || times < threshold {
+		return "", 0
+	}
+	return top, times
+}
@@ -0,0 +1,298 @@
+package logx
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+)
+
+const (
+	tracerName = "github.com/lpphub/goweb/ext/logx"
+
+	ctxKeyTraceID = "trace_id"
+	ctxKeySpanID  = "span_id"
+)
+
+type traceConfig struct {
+	provider   trace.TracerProvider
+	propagator propagation.TextMapPropagator
+	skipPaths  map[string]struct{}
+	sqlMaxLen  int
+}
+
+type TraceOption func(*traceConfig)
+
+func newTraceConfig(opts []TraceOption) *traceConfig {
+	cfg := &traceConfig{
+		skipPaths: make(map[string]struct{}),
+		sqlMaxLen: 1024,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	// 未指定时使用 otel 全局配置
+	if cfg.provider == nil {
+		cfg.provider = otel.GetTracerProvider()
+	}
+	if cfg.propagator == nil {
+		cfg.propagator = propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{})
+	}
+
+	registerTraceFields()
+	return cfg
+}
+
+func (cfg *traceConfig) tracer() trace.Tracer {
+	return cfg.provider.Tracer(tracerName)
+}
+
+// WithTracerProvider 指定 TracerProvider，默认使用 otel.GetTracerProvider()
+func WithTracerProvider(tp trace.TracerProvider) TraceOption {
+	return func(cfg *traceConfig) {
+		cfg.provider = tp
+	}
+}
+
+// WithPropagator 指定上下文传播器，默认 W3C traceparent + baggage
+func WithPropagator(p propagation.TextMapPropagator) TraceOption {
+	return func(cfg *traceConfig) {
+		cfg.propagator = p
+	}
+}
+
+// WithTraceSkipPaths 跳过指定 Gin 路由，如 /health
+func WithTraceSkipPaths(paths ...string) TraceOption {
+	return func(cfg *traceConfig) {
+		for _, p := range paths {
+			if p != "" {
+				cfg.skipPaths[p] = struct{}{}
+			}
+		}
+	}
+}
+
+var traceFieldsOnce sync.Once
+
+// registerTraceFields 为日志自动附加当前 span 的 trace_id/span_id
+func registerTraceFields() {
+	traceFieldsOnce.Do(func() {
+		logging.RegisterContextExtractor(func(ctx context.Context) []logging.Field {
+			sc := trace.SpanContextFromContext(ctx)
+			if !sc.IsValid() {
+				return nil
+			}
+			return []logging.Field{
+				logging.Str(ctxKeyTraceID, sc.TraceID().String()),
+				logging.Str(ctxKeySpanID, sc.SpanID().String()),
+			}
+		})
+	})
+}
+
+// GinTrace Gin 链路追踪中间件：提取上游 traceparent，创建 server span，并在响应头中回写
+func GinTrace(opts ...TraceOption) gin.HandlerFunc {
+	cfg := newTraceConfig(opts)
+	tracer := cfg.tracer()
+
+	return func(c *gin.Context) {
+		route := c.FullPath()
+		if _, ok := cfg.skipPaths[route]; ok {
+			c.Next()
+			return
+		}
+
+		ctx := cfg.propagator.Extract(c.Request.Context(), propagation.HeaderCarrier(c.Request.Header))
+
+		spanName := c.Request.Method
+		if route != "" {
+			spanName += " " + route
+		}
+		ctx, span := tracer.Start(ctx, spanName,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+				attribute.String("client.address", c.ClientIP()),
+			),
+		)
+		defer span.End()
+
+		cfg.propagator.Inject(ctx, propagation.HeaderCarrier(c.Writer.Header()))
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		for _, e := range c.Errors {
+			span.RecordError(e.Err)
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, strconv.Itoa(status))
+		}
+	}
+}
+
+const gormSpanKey = "logx:span"
+
+// GormTracing GORM 链路追踪插件，为每条 SQL 创建子 span（仅记录 SQL 模板，不含参数）
+type GormTracing struct {
+	cfg    *traceConfig
+	tracer trace.Tracer
+}
+
+// NewGormTracing 创建 GORM 链路追踪插件，通过 db.Use 注册
+func NewGormTracing(opts ...TraceOption) *GormTracing {
+	cfg := newTraceConfig(opts)
+	return &GormTracing{cfg: cfg, tracer: cfg.tracer()}
+}
+
+func (t *GormTracing) Name() string {
+	return "logx:tracing"
+}
+
+// Initialize 注册 gorm callbacks
+func (t *GormTracing) Initialize(db *gorm.DB) error {
+	cb := db.Callback()
+	hooks := []struct {
+		name   string
+		before func(name string, fn func(*gorm.DB)) error
+		after  func(name string, fn func(*gorm.DB)) error
+	}{
+		{"create", cb.Create().Before("gorm:create").Register, cb.Create().After("gorm:create").Register},
+		{"query", cb.Query().Before("gorm:query").Register, cb.Query().After("gorm:query").Register},
+		{"update", cb.Update().Before("gorm:update").Register, cb.Update().After("gorm:update").Register},
+		{"delete", cb.Delete().Before("gorm:delete").Register, cb.Delete().After("gorm:delete").Register},
+		{"row", cb.Row().Before("gorm:row").Register, cb.Row().After("gorm:row").Register},
+		{"raw", cb.Raw().Before("gorm:raw").Register, cb.Raw().After("gorm:raw").Register},
+	}
+
+	for _, h := range hooks {
+		if err := h.before("logx:trace_before_"+h.name, t.before(h.name)); err != nil {
+			return err
+		}
+		if err := h.after("logx:trace_after_"+h.name, t.after); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (t *GormTracing) before(op string) func(*gorm.DB) {
+	return func(db *gorm.DB) {
+		if db.Statement == nil || db.Statement.Context == nil {
+			return
+		}
+		ctx, span := t.tracer.Start(db.Statement.Context, "gorm."+op,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.operation.name", op)),
+		)
+		// 替换 Statement.Context，使 GormLogger 输出的日志关联到该 span
+		db.Statement.Context = ctx
+		db.InstanceSet(gormSpanKey, span)
+	}
+}
+
+func (t *GormTracing) after(db *gorm.DB) {
+	v, ok := db.InstanceGet(gormSpanKey)
+	if !ok {
+		return
+	}
+	span, ok := v.(trace.Span)
+	if !ok {
+		return
+	}
+	defer span.End()
+
+	sql := db.Statement.SQL.String()
+	if len(sql) > t.cfg.sqlMaxLen {
+		sql = sql[:t.cfg.sqlMaxLen] + " ...[truncated]"
+	}
+	span.SetAttributes(
+		attribute.String("db.system", db.Dialector.Name()),
+		attribute.String("db.statement", sql),
+		attribute.String("db.sql.table", db.Statement.Table),
+		attribute.Int64("db.rows_affected", db.RowsAffected),
+	)
+
+	if err := db.Error; err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+}
+
+// RedisTracing go-redis 链路追踪钩子，为每条命令创建子 span（仅记录命令名，不含参数）
+type RedisTracing struct {
+	tracer trace.Tracer
+}
+
+// NewRedisTracing 创建 go-redis 链路追踪钩子，通过 client.AddHook 注册
+func NewRedisTracing(opts ...TraceOption) *RedisTracing {
+	cfg := newTraceConfig(opts)
+	return &RedisTracing{tracer: cfg.tracer()}
+}
+
+func (t *RedisTracing) DialHook(next redis.DialHook) redis.DialHook {
+	return func(ctx context.Context, network, addr string) (net.Conn, error) {
+		ctx, span := t.tracer.Start(ctx, "redis.dial",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "redis"), attribute.String("server.address", addr)),
+		)
+		defer span.End()
+
+		conn, err := next(ctx, network, addr)
+		recordSpanError(span, err)
+		return conn, err
+	}
+}
+
+func (t *RedisTracing) ProcessHook(next redis.ProcessHook) redis.ProcessHook {
+	return func(ctx context.Context, cmd redis.Cmder) error {
+		ctx, span := t.tracer.Start(ctx, "redis."+cmd.Name(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "redis"), attribute.String("db.operation.name", cmd.Name())),
+		)
+		defer span.End()
+
+		err := next(ctx, cmd)
+		recordSpanError(span, err)
+		return err
+	}
+}
+
+func (t *RedisTracing) ProcessPipelineHook(next redis.ProcessPipelineHook) redis.ProcessPipelineHook {
+	return func(ctx context.Context, cmds []redis.Cmder) error {
+		ctx, span := t.tracer.Start(ctx, "redis.pipeline",
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attribute.String("db.system", "redis"), attribute.Int("db.redis.num_cmd", len(cmds))),
+		)
+		defer span.End()
+
+		err := next(ctx, cmds)
+		recordSpanError(span, err)
+		return err
+	}
+}
+
+func recordSpanError(span trace.Span, err error) {
+	if err == nil || errors.Is(err, redis.Nil) {
+		return
+	}
+	span.RecordError(err)
+	span.SetStatus(codes.Error, err.Error())
+}
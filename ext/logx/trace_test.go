@@ -0,0 +1,182 @@
+package logx
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"gorm.io/gorm"
+	"gorm.io/gorm/callbacks"
+	"gorm.io/gorm/clause"
+	"gorm.io/gorm/logger"
+	"gorm.io/gorm/schema"
+)
+
+func TestGinTrace(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var buf bytes.Buffer
+	logging.Init(logging.WithOutput(&buf))
+	defer logging.Init()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	engine := gin.New()
+	engine.Use(GinTrace(WithTracerProvider(tp)))
+	engine.GET("/users/:id", func(c *gin.Context) {
+		logging.Info(c.Request.Context(), "handle user")
+		c.Status(http.StatusOK)
+	})
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/users/1", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	w := httptest.NewRecorder()
+	engine.ServeHTTP(w, req)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 1)
+	assert.Equal(t, "GET /users/:id", spans[0].Name)
+	assert.Equal(t, traceID, spans[0].SpanContext.TraceID().String())
+	assert.Equal(t, "00f067aa0ba902b7", spans[0].Parent.SpanID().String())
+
+	assert.Contains(t, w.Header().Get("traceparent"), traceID)
+	assert.Contains(t, buf.String(), `"trace_id":"`+traceID+`"`)
+	assert.Contains(t, buf.String(), `"span_id":"`+spans[0].SpanContext.SpanID().String()+`"`)
+}
+
+func TestRedisTracing(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+	hook := NewRedisTracing(WithTracerProvider(tp))
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	defer parent.End()
+
+	var gotCtx context.Context
+	process := hook.ProcessHook(func(ctx context.Context, cmd redis.Cmder) error {
+		gotCtx = ctx
+		return errors.New("conn refused")
+	})
+	pipeline := hook.ProcessPipelineHook(func(ctx context.Context, cmds []redis.Cmder) error {
+		return redis.Nil
+	})
+
+	require.Error(t, process(ctx, redis.NewStringCmd(ctx, "get", "k")))
+	require.ErrorIs(t, pipeline(ctx, []redis.Cmder{redis.NewStatusCmd(ctx, "ping"), redis.NewStringCmd(ctx, "get", "k")}), redis.Nil)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	get := spans[0]
+	assert.Equal(t, "redis.get", get.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), get.Parent.SpanID())
+	assert.Equal(t, get.SpanContext.SpanID(), trace.SpanContextFromContext(gotCtx).SpanID(), "next 应收到子 span 的 ctx")
+	assert.Equal(t, codes.Error, get.Status.Code)
+	assert.Equal(t, "conn refused", get.Status.Description)
+
+	pipe := spans[1]
+	assert.Equal(t, "redis.pipeline", pipe.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), pipe.Parent.SpanID())
+	assert.Equal(t, codes.Unset, pipe.Status.Code, "redis.Nil 不视为错误")
+	assert.Contains(t, pipe.Attributes, attribute.Int("db.redis.num_cmd", 2))
+}
+
+func TestGormTracing(t *testing.T) {
+	var buf bytes.Buffer
+	logging.Init(logging.WithOutput(&buf))
+	defer logging.Init()
+
+	exporter := tracetest.NewInMemoryExporter()
+	tp := sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter))
+
+	db, err := gorm.Open(stubDialector{}, &gorm.Config{DryRun: true, Logger: NewGormLogger()})
+	require.NoError(t, err)
+	require.NoError(t, db.Use(NewGormTracing(WithTracerProvider(tp))))
+	require.NoError(t, db.Callback().Query().Before("gorm:query").Register("test:fail", func(db *gorm.DB) {
+		if db.Statement.Table == "orders" {
+			_ = db.AddError(errors.New("deadlock"))
+		}
+	}))
+
+	type User struct {
+		ID   int
+		Name string
+	}
+	type Order struct {
+		ID int
+	}
+
+	ctx, parent := tp.Tracer("test").Start(context.Background(), "parent")
+	defer parent.End()
+
+	var users []User
+	require.NoError(t, db.WithContext(ctx).Where("name = ?", "alice").Find(&users).Error)
+	var orders []Order
+	require.Error(t, db.WithContext(ctx).Find(&orders).Error)
+
+	spans := exporter.GetSpans()
+	require.Len(t, spans, 2)
+
+	q := spans[0]
+	assert.Equal(t, "gorm.query", q.Name)
+	assert.Equal(t, parent.SpanContext().SpanID(), q.Parent.SpanID())
+	assert.Equal(t, codes.Unset, q.Status.Code)
+	assert.Contains(t, q.Attributes, attribute.String("db.system", "stub"))
+	assert.Contains(t, q.Attributes, attribute.String("db.sql.table", "users"))
+	assert.Contains(t, q.Attributes, attribute.String("db.statement", "SELECT * FROM `users` WHERE name = ?"))
+
+	assert.Equal(t, parent.SpanContext().SpanID(), spans[1].Parent.SpanID())
+	assert.Equal(t, codes.Error, spans[1].Status.Code)
+	assert.Equal(t, "deadlock", spans[1].Status.Description)
+
+	// GormLogger 使用替换后的 Statement.Context，SQL 日志关联到 gorm span
+	line, _, _ := bytes.Cut(buf.Bytes(), []byte("\n"))
+	assert.Contains(t, string(line), `"span_id":"`+q.SpanContext.SpanID().String()+`"`)
+}
+
+// stubDialector 仅生成 SQL，配合 DryRun 使用，不连接数据库
+type stubDialector struct{}
+
+func (stubDialector) Name() string { return "stub" }
+
+func (stubDialector) Initialize(db *gorm.DB) error {
+	callbacks.RegisterDefaultCallbacks(db, &callbacks.Config{})
+	return nil
+}
+
+func (stubDialector) Migrator(*gorm.DB) gorm.Migrator { return nil }
+
+func (stubDialector) DataTypeOf(*schema.Field) string { return "" }
+
+func (stubDialector) DefaultValueOf(*schema.Field) clause.Expression {
+	return clause.Expr{SQL: "DEFAULT"}
+}
+
+func (stubDialector) BindVarTo(w clause.Writer, _ *gorm.Statement, _ interface{}) {
+	_ = w.WriteByte('?')
+}
+
+func (stubDialector) QuoteTo(w clause.Writer, s string) {
+	_ = w.WriteByte('`')
+	_, _ = w.WriteString(s)
+	_ = w.WriteByte('`')
+}
+
+func (stubDialector) Explain(sql string, vars ...interface{}) string {
+	return logger.ExplainSQL(sql, nil, `'`, vars...)
+}
@@ -0,0 +1,90 @@
+package logx
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/lpphub/goweb/pkg/logging"
+)
+
+type transportConfig struct {
+	requestIDHeader string
+	fieldHeaders    map[string]string // logging field key -> header
+}
+
+type TransportOption func(*transportConfig)
+
+// WithTransportRequestIDHeader 设置出站请求携带 requestId 的 Header，默认 X-Request-ID
+func WithTransportRequestIDHeader(name string) TransportOption {
+	return func(cfg *transportConfig) {
+		if name != "" {
+			cfg.requestIDHeader = name
+		}
+	}
+}
+
+// WithTransportField 将 ctx 中的 logging field 写入出站请求 Header
+func WithTransportField(field, header string) TransportOption {
+	return func(cfg *transportConfig) {
+		if field != "" && header != "" {
+			cfg.fieldHeaders[field] = header
+		}
+	}
+}
+
+// Transport 出站请求传递 requestId 及日志字段的 http.RoundTripper
+type Transport struct {
+	base http.RoundTripper
+	cfg  *transportConfig
+}
+
+// NewTransport 包装 base（nil 时使用 http.DefaultTransport）
+func NewTransport(base http.RoundTripper, opts ...TransportOption) *Transport {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+
+	cfg := &transportConfig{
+		requestIDHeader: HeaderRequestID,
+		fieldHeaders:    make(map[string]string),
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	return &Transport{base: base, cfg: cfg}
+}
+
+// RoundTrip 实现 http.RoundTripper，不修改调用方的原始请求
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	ctx := req.Context()
+	requestID := RequestIDFrom(ctx)
+
+	var fields map[string]any
+	if len(t.cfg.fieldHeaders) > 0 {
+		fields = logging.FieldsMap(ctx)
+	}
+
+	if requestID == "" && len(fields) == 0 {
+		return t.base.RoundTrip(req)
+	}
+
+	out := req.Clone(ctx)
+	if requestID != "" && out.Header.Get(t.cfg.requestIDHeader) == "" {
+		out.Header.Set(t.cfg.requestIDHeader, requestID)
+	}
+	for field, header := range t.cfg.fieldHeaders {
+		v, ok := fields[field]
+		if !ok || out.Header.Get(header) != "" {
+			continue
+		}
+		// 跳过含换行等控制字符的值，避免非法 Header 导致请求失败
+		if s := fmt.Sprint(v); !strings.ContainsFunc(s, unicode.IsControl) {
+			out.Header.Set(header, s)
+		}
+	}
+
+	return t.base.RoundTrip(out)
+}
@@ -0,0 +1,10 @@
+//go:build !unix
+
+package monitor
+
+import "time"
+
+// processCPUTime 当前平台不支持，CPU 触发器不生效
+func processCPUTime() (time.Duration, bool) {
+	return 0, false
+}
@@ -0,0 +1,17 @@
+//go:build unix
+
+package monitor
+
+import (
+	"syscall"
+	"time"
+)
+
+// processCPUTime 进程累计 CPU 时间（user + system）
+func processCPUTime() (time.Duration, bool) {
+	var ru syscall.Rusage
+	if err := syscall.Getrusage(syscall.RUSAGE_SELF, &ru); err != nil {
+		return 0, false
+	}
+	return time.Duration(ru.Utime.Nano() + ru.Stime.Nano()), true
+}
@@ -0,0 +1,327 @@
+package monitor
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultLivezPath    = "/livez"
+	defaultReadyzPath   = "/readyz"
+	defaultCheckTimeout = time.Second
+)
+
+// ErrNotReady 服务已标记为未就绪（如正在关闭）
+var ErrNotReady = errors.New("service not ready")
+
+// Checker 健康检查项
+type Checker interface {
+	Name() string
+	Check(ctx context.Context) error
+}
+
+type checkerFunc struct {
+	name string
+	fn   func(ctx context.Context) error
+}
+
+func (c checkerFunc) Name() string                    { return c.name }
+func (c checkerFunc) Check(ctx context.Context) error { return c.fn(ctx) }
+
+// NewChecker 使用函数创建检查项
+func NewChecker(name string, fn func(ctx context.Context) error) Checker {
+	return checkerFunc{name: name, fn: fn}
+}
+
+// DBChecker 检查数据库连接（Ping）
+func DBChecker(name string, db *gorm.DB) Checker {
+	return NewChecker(name, func(ctx context.Context) error {
+		sqlDB, err := db.DB()
+		if err != nil {
+			return err
+		}
+		return sqlDB.PingContext(ctx)
+	})
+}
+
+// RedisChecker 检查 redis 连接（PING）
+func RedisChecker(name string, client redis.UniversalClient) Checker {
+	return NewChecker(name, func(ctx context.Context) error {
+		return client.Ping(ctx).Err()
+	})
+}
+
+type checkConfig struct {
+	timeout  time.Duration
+	cacheTTL time.Duration
+}
+
+type CheckOption func(*checkConfig)
+
+// WithCheckTimeout 设置单个检查超时，默认 1s
+func WithCheckTimeout(d time.Duration) CheckOption {
+	return func(cfg *checkConfig) {
+		if d > 0 {
+			cfg.timeout = d
+		}
+	}
+}
+
+// WithCheckCacheTTL 缓存成功的检查结果，避免探针频繁访问下游；失败结果不缓存，以便下游恢复后及时就绪
+func WithCheckCacheTTL(d time.Duration) CheckOption {
+	return func(cfg *checkConfig) {
+		cfg.cacheTTL = d
+	}
+}
+
+// CheckResult 单个检查结果
+type CheckResult struct {
+	Name       string `json:"name"`
+	Status     string `json:"status"`
+	Error      string `json:"error,omitempty"`
+	DurationMs int64  `json:"duration_ms"`
+	Cached     bool   `json:"cached,omitempty"`
+}
+
+type check struct {
+	checker Checker
+	cfg     checkConfig
+
+	mu      sync.Mutex
+	last    CheckResult
+	checked time.Time
+	running *pendingCheck // 执行中的检查，并发探针共用，避免不响应 ctx 的检查项堆积
+}
+
+type pendingCheck struct {
+	done chan struct{}
+	err  error
+}
+
+// run 执行检查，超时即返回失败，不等待不响应 ctx 的检查项
+func (c *check) run(ctx context.Context) CheckResult {
+	c.mu.Lock()
+	if c.cfg.cacheTTL > 0 && !c.checked.IsZero() && time.Since(c.checked) < c.cfg.cacheTTL {
+		res := c.last
+		c.mu.Unlock()
+		res.Cached = true
+		return res
+	}
+	p := c.running
+	if p == nil {
+		p = &pendingCheck{done: make(chan struct{})}
+		c.running = p
+		go c.exec(p)
+	}
+	c.mu.Unlock()
+
+	start := time.Now()
+	timer := time.NewTimer(c.cfg.timeout)
+	defer timer.Stop()
+
+	var err error
+	select {
+	case <-p.done:
+		err = p.err
+	case <-timer.C:
+		err = fmt.Errorf("check timeout after %s", c.cfg.timeout)
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	res := CheckResult{
+		Name:       c.checker.Name(),
+		Status:     statusOK,
+		DurationMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		res.Status = statusFail
+		res.Error = err.Error()
+	}
+	return res
+}
+
+// exec 在后台执行检查，成功时更新缓存
+func (c *check) exec(p *pendingCheck) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.cfg.timeout)
+	defer cancel()
+
+	start := time.Now()
+	p.err = c.checker.Check(ctx)
+	close(p.done)
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.running = nil
+	if p.err == nil {
+		c.last = CheckResult{Name: c.checker.Name(), Status: statusOK, DurationMs: time.Since(start).Milliseconds()}
+		c.checked = time.Now()
+	}
+}
+
+const (
+	statusOK   = "ok"
+	statusFail = "fail"
+)
+
+// HealthReport 检查汇总结果
+type HealthReport struct {
+	Status string        `json:"status"`
+	Checks []CheckResult `json:"checks"`
+}
+
+type healthConfig struct {
+	livezPath  string
+	readyzPath string
+}
+
+type HealthOption func(*healthConfig)
+
+// WithLivezPath 设置存活探针路径，默认 /livez
+func WithLivezPath(path string) HealthOption {
+	return func(cfg *healthConfig) {
+		if path != "" {
+			cfg.livezPath = path
+		}
+	}
+}
+
+// WithReadyzPath 设置就绪探针路径，默认 /readyz
+func WithReadyzPath(path string) HealthOption {
+	return func(cfg *healthConfig) {
+		if path != "" {
+			cfg.readyzPath = path
+		}
+	}
+}
+
+// Health 存活/就绪探针
+type Health struct {
+	cfg *healthConfig
+
+	mu        sync.RWMutex
+	liveness  []*check
+	readiness []*check
+
+	notReady atomic.Bool
+}
+
+// NewHealth 创建健康检查
+func NewHealth(opts ...HealthOption) *Health {
+	cfg := &healthConfig{
+		livezPath:  defaultLivezPath,
+		readyzPath: defaultReadyzPath,
+	}
+	for _, opt := range opts {
+		opt(cfg)
+	}
+	return &Health{cfg: cfg}
+}
+
+// AddLiveness 添加存活检查项（失败会导致容器重启，应只检查进程自身）
+func (h *Health) AddLiveness(c Checker, opts ...CheckOption) *Health {
+	h.mu.Lock()
+	h.liveness = append(h.liveness, newCheck(c, opts))
+	h.mu.Unlock()
+	return h
+}
+
+// AddReadiness 添加就绪检查项（如数据库、redis）
+func (h *Health) AddReadiness(c Checker, opts ...CheckOption) *Health {
+	h.mu.Lock()
+	h.readiness = append(h.readiness, newCheck(c, opts))
+	h.mu.Unlock()
+	return h
+}
+
+func newCheck(c Checker, opts []CheckOption) *check {
+	cfg := checkConfig{timeout: defaultCheckTimeout}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &check{checker: c, cfg: cfg}
+}
+
+// SetReady 设置就绪状态，优雅关闭开始时置为 false 使流量摘除
+func (h *Health) SetReady(ready bool) {
+	h.notReady.Store(!ready)
+}
+
+// Live 执行存活检查
+func (h *Health) Live(ctx context.Context) HealthReport {
+	h.mu.RLock()
+	checks := h.liveness
+	h.mu.RUnlock()
+	return runChecks(ctx, checks)
+}
+
+// Ready 执行就绪检查
+func (h *Health) Ready(ctx context.Context) HealthReport {
+	if h.notReady.Load() {
+		return HealthReport{
+			Status: statusFail,
+			Checks: []CheckResult{{Name: "shutdown", Status: statusFail, Error: ErrNotReady.Error()}},
+		}
+	}
+
+	h.mu.RLock()
+	checks := h.readiness
+	h.mu.RUnlock()
+	return runChecks(ctx, checks)
+}
+
+// Register 在 engine 上注册 /livez 与 /readyz，?verbose=1 时返回详细 JSON
+func (h *Health) Register(r gin.IRoutes) {
+	r.GET(h.cfg.livezPath, h.handle(h.Live))
+	r.GET(h.cfg.readyzPath, h.handle(h.Ready))
+}
+
+func (h *Health) handle(run func(ctx context.Context) HealthReport) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report := run(c.Request.Context())
+
+		status := http.StatusOK
+		if report.Status != statusOK {
+			status = http.StatusServiceUnavailable
+		}
+
+		if verbose, _ := strconv.ParseBool(c.Query("verbose")); verbose {
+			c.JSON(status, report)
+			return
+		}
+		c.String(status, report.Status)
+	}
+}
+
+// runChecks 并发执行检查项
+func runChecks(ctx context.Context, checks []*check) HealthReport {
+	report := HealthReport{Status: statusOK, Checks: make([]CheckResult, len(checks))}
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Add(1)
+		go func(i int, c *check) {
+			defer wg.Done()
+			report.Checks[i] = c.run(ctx)
+		}(i, c)
+	}
+	wg.Wait()
+
+	for _, res := range report.Checks {
+		if res.Status != statusOK {
+			report.Status = statusFail
+			break
+		}
+	}
+	return report
+}
@@ -0,0 +1,124 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	var calls atomic.Int32
+
+	h := NewHealth().
+		AddLiveness(NewChecker("ping", func(ctx context.Context) error { return nil })).
+		AddReadiness(NewChecker("db", func(ctx context.Context) error {
+			calls.Add(1)
+			return nil
+		}), WithCheckCacheTTL(time.Minute)).
+		AddReadiness(NewChecker("slow", func(ctx context.Context) error {
+			<-ctx.Done()
+			return ctx.Err()
+		}), WithCheckTimeout(10*time.Millisecond))
+
+	engine := gin.New()
+	h.Register(engine)
+
+	serve := func(path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(http.MethodGet, path, nil))
+		return w
+	}
+
+	t.Run("Livez", func(t *testing.T) {
+		w := serve("/livez")
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "ok", w.Body.String())
+	})
+
+	t.Run("ReadyzVerbose", func(t *testing.T) {
+		assert.Equal(t, "fail", serve("/readyz?verbose=0").Body.String())
+
+		w := serve("/readyz?verbose=1")
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+
+		var report HealthReport
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &report))
+		require.Len(t, report.Checks, 2)
+		assert.Equal(t, statusOK, report.Checks[0].Status)
+		assert.Equal(t, statusFail, report.Checks[1].Status)
+
+		// 缓存期内不重复执行
+		serve("/readyz")
+		assert.Equal(t, int32(1), calls.Load())
+	})
+
+	t.Run("StuckChecker", func(t *testing.T) {
+		// 不响应 ctx 的检查项按超时返回失败，并发探针共用同一次执行
+		release := make(chan struct{})
+		var stuck atomic.Int32
+		h := NewHealth().AddReadiness(NewChecker("stuck", func(ctx context.Context) error {
+			stuck.Add(1)
+			<-release
+			return nil
+		}), WithCheckTimeout(10*time.Millisecond))
+
+		start := time.Now()
+		var wg sync.WaitGroup
+		for range 5 {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				report := h.Ready(context.Background())
+				assert.Equal(t, statusFail, report.Status)
+				assert.Contains(t, report.Checks[0].Error, "timeout")
+			}()
+		}
+		wg.Wait()
+		assert.Less(t, time.Since(start), time.Second)
+		assert.Equal(t, int32(1), stuck.Load())
+
+		close(release)
+		assert.Eventually(t, func() bool { return h.Ready(context.Background()).Status == statusOK }, time.Second, 5*time.Millisecond)
+	})
+
+	t.Run("FailureNotCached", func(t *testing.T) {
+		var down atomic.Bool
+		down.Store(true)
+		h := NewHealth().AddReadiness(NewChecker("db", func(ctx context.Context) error {
+			if down.Load() {
+				return errors.New("connection refused")
+			}
+			return nil
+		}), WithCheckCacheTTL(time.Minute))
+
+		assert.Equal(t, statusFail, h.Ready(context.Background()).Status)
+		down.Store(false)
+		assert.Equal(t, statusOK, h.Ready(context.Background()).Status)
+
+		down.Store(true)
+		report := h.Ready(context.Background())
+		assert.Equal(t, statusOK, report.Status, "成功结果在缓存期内复用")
+		assert.True(t, report.Checks[0].Cached)
+	})
+
+	t.Run("NotReady", func(t *testing.T) {
+		h.SetReady(false)
+		defer h.SetReady(true)
+
+		report := h.Ready(context.Background())
+		assert.Equal(t, statusFail, report.Status)
+		assert.Equal(t, "shutdown", report.Checks[0].Name)
+	})
+}
@@ -1,6 +1,11 @@
 package monitor
 
 import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -8,40 +13,447 @@ import (
 	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
-var (
-	httpRequestsTotal = prometheus.NewCounterVec(
-		prometheus.CounterOpts{
-			Name: "http_requests_total",
-			Help: "Total number of HTTP requests",
-		},
-		[]string{"method", "path"},
-	)
-	httpRequestDurationHistogram = prometheus.NewHistogramVec(prometheus.HistogramOpts{
-		Name:    "http_request_duration_seconds",
-		Help:    "Histogram of the duration of HTTP requests",
-		Buckets: prometheus.DefBuckets,
-	}, []string{"method", "path"})
+// 可选的指标 label
+const (
+	LabelMethod = "method"
+	LabelPath   = "path"
+	LabelStatus = "status" // 状态码分类，如 2xx、4xx
 )
 
-func init() {
-	prometheus.MustRegister(httpRequestsTotal)
-	prometheus.MustRegister(httpRequestDurationHistogram)
+const (
+	defaultMetricsPath = "/metrics"
+	// UnmatchedPath 未匹配路由统一使用的 path label，避免 404 扫描导致 label 基数膨胀
+	UnmatchedPath = "<unmatched>"
+)
+
+var defaultSizeBuckets = prometheus.ExponentialBuckets(100, 10, 6) // 100B ~ 10MB
+
+type metricsConfig struct {
+	labels        []string
+	unmatchedPath string
+
+	registerer prometheus.Registerer
+	gatherer   prometheus.Gatherer
+	namespace  string
+	subsystem  string
+	buckets    []float64
+
+	inFlight    bool
+	sizeMetrics bool
+	sizeBuckets []float64
+
+	path      string
+	addr      string // 非空时在独立端口提供 /metrics
+	basicAuth map[string]string
+}
+
+type MetricsOption func(*metricsConfig)
+
+func defaultMetricsConfig() *metricsConfig {
+	return &metricsConfig{
+		labels:        []string{LabelMethod, LabelPath, LabelStatus},
+		unmatchedPath: UnmatchedPath,
+		registerer:    prometheus.DefaultRegisterer,
+		gatherer:      prometheus.DefaultGatherer,
+		buckets:       prometheus.DefBuckets,
+		sizeBuckets:   defaultSizeBuckets,
+		path:          defaultMetricsPath,
+	}
+}
+
+// WithLabels 设置允许的 label 白名单，未知 label 会被忽略
+func WithLabels(labels ...string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		allowed := make([]string, 0, len(labels))
+		seen := make(map[string]struct{}, len(labels))
+		for _, l := range labels {
+			switch l {
+			case LabelMethod, LabelPath, LabelStatus:
+			default:
+				continue
+			}
+			if _, ok := seen[l]; ok {
+				continue
+			}
+			seen[l] = struct{}{}
+			allowed = append(allowed, l)
+		}
+		cfg.labels = allowed
+	}
 }
 
-func RegisterMetrics(engine *gin.Engine) {
-	engine.Use(func(c *gin.Context) {
-		if c.Request.URL.Path == "/metrics" {
+// WithUnmatchedPath 设置未匹配路由使用的 path label
+func WithUnmatchedPath(path string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		if path != "" {
+			cfg.unmatchedPath = path
+		}
+	}
+}
+
+// WithRegisterer 使用自定义 Registerer 注册指标，若其同时实现 Gatherer 则 /metrics 也从中采集
+func WithRegisterer(reg prometheus.Registerer) MetricsOption {
+	return func(cfg *metricsConfig) {
+		if reg == nil {
 			return
 		}
+		cfg.registerer = reg
+		if g, ok := reg.(prometheus.Gatherer); ok {
+			cfg.gatherer = g
+		}
+	}
+}
+
+// WithGatherer 设置 /metrics 采集来源
+func WithGatherer(g prometheus.Gatherer) MetricsOption {
+	return func(cfg *metricsConfig) {
+		if g != nil {
+			cfg.gatherer = g
+		}
+	}
+}
+
+// WithNamespace 设置指标 namespace
+func WithNamespace(namespace string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.namespace = namespace
+	}
+}
+
+// WithSubsystem 设置指标 subsystem
+func WithSubsystem(subsystem string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.subsystem = subsystem
+	}
+}
+
+// WithBuckets 设置请求耗时直方图的 buckets（秒）
+func WithBuckets(buckets ...float64) MetricsOption {
+	return func(cfg *metricsConfig) {
+		if len(buckets) > 0 {
+			cfg.buckets = buckets
+		}
+	}
+}
+
+// WithInFlight 启用处理中请求数 gauge
+func WithInFlight() MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.inFlight = true
+	}
+}
+
+// WithSizeMetrics 启用请求/响应大小直方图，buckets 为空时使用默认值（字节）
+func WithSizeMetrics(buckets ...float64) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.sizeMetrics = true
+		if len(buckets) > 0 {
+			cfg.sizeBuckets = buckets
+		}
+	}
+}
+
+// WithMetricsPath 设置指标暴露路径，默认 /metrics
+func WithMetricsPath(path string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		if path != "" {
+			cfg.path = path
+		}
+	}
+}
+
+// WithMetricsAddr 在独立端口（如 :9090）暴露指标，而不是挂载到业务 engine
+func WithMetricsAddr(addr string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		cfg.addr = addr
+	}
+}
+
+// WithBasicAuth 为指标暴露路径启用 basic auth
+func WithBasicAuth(user, password string) MetricsOption {
+	return func(cfg *metricsConfig) {
+		if cfg.basicAuth == nil {
+			cfg.basicAuth = make(map[string]string)
+		}
+		cfg.basicAuth[user] = password
+	}
+}
+
+// Metrics HTTP 请求指标
+type Metrics struct {
+	cfg *metricsConfig
+
+	requestsTotal   *prometheus.CounterVec
+	requestDuration *prometheus.HistogramVec
+	requestSize     *prometheus.HistogramVec
+	responseSize    *prometheus.HistogramVec
+	inFlight        prometheus.Gauge
+	panicsTotal     *prometheus.CounterVec
+
+	server *server
+}
+
+// NewMetrics 创建并注册 HTTP 请求指标，同一 Registerer 上已存在的同名指标直接复用
+func NewMetrics(opts ...MetricsOption) (*Metrics, error) {
+	cfg := defaultMetricsConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	m := &Metrics{cfg: cfg}
+	m.requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: cfg.namespace,
+		Subsystem: cfg.subsystem,
+		Name:      "http_requests_total",
+		Help:      "Total number of HTTP requests",
+	}, cfg.labels)
+	m.requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: cfg.namespace,
+		Subsystem: cfg.subsystem,
+		Name:      "http_request_duration_seconds",
+		Help:      "Histogram of the duration of HTTP requests",
+		Buckets:   cfg.buckets,
+	}, cfg.labels)
+	m.panicsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: cfg.namespace,
+		Subsystem: cfg.subsystem,
+		Name:      "http_panics_total",
+		Help:      "Total number of panics recovered in HTTP handlers",
+	}, []string{LabelPath})
+
+	if cfg.sizeMetrics {
+		m.requestSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Subsystem: cfg.subsystem,
+			Name:      "http_request_size_bytes",
+			Help:      "Histogram of the size of HTTP requests",
+			Buckets:   cfg.sizeBuckets,
+		}, cfg.labels)
+		m.responseSize = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: cfg.namespace,
+			Subsystem: cfg.subsystem,
+			Name:      "http_response_size_bytes",
+			Help:      "Histogram of the size of HTTP responses",
+			Buckets:   cfg.sizeBuckets,
+		}, cfg.labels)
+	}
+
+	if cfg.inFlight {
+		m.inFlight = prometheus.NewGauge(prometheus.GaugeOpts{
+			Namespace: cfg.namespace,
+			Subsystem: cfg.subsystem,
+			Name:      "http_requests_in_flight",
+			Help:      "Number of HTTP requests currently being served",
+		})
+	}
+
+	r := &registrar{reg: cfg.registerer}
+	m.requestsTotal = registerAs(r, m.requestsTotal)
+	m.requestDuration = registerAs(r, m.requestDuration)
+	m.panicsTotal = registerAs(r, m.panicsTotal)
+	if m.requestSize != nil {
+		m.requestSize = registerAs(r, m.requestSize)
+		m.responseSize = registerAs(r, m.responseSize)
+	}
+	if m.inFlight != nil {
+		m.inFlight = registerAs(r, m.inFlight)
+	}
+	if r.err != nil {
+		r.rollback()
+		return nil, r.err
+	}
+
+	return m, nil
+}
+
+// registrar 依次注册指标，出错后跳过剩余指标，并可回滚本次新注册的指标
+type registrar struct {
+	reg   prometheus.Registerer
+	added []prometheus.Collector
+	err   error
+}
+
+// registerAs 注册指标，已存在同名同 label 的指标时复用已有实例，
+// 使多次 RegisterMetrics（如多个 engine 共用默认 registry）不会失败
+func registerAs[T prometheus.Collector](r *registrar, c T) T {
+	if r.err != nil {
+		return c
+	}
+	err := r.reg.Register(c)
+	if err == nil {
+		r.added = append(r.added, c)
+		return c
+	}
+	var are prometheus.AlreadyRegisteredError
+	if errors.As(err, &are) {
+		if existing, ok := are.ExistingCollector.(T); ok {
+			return existing
+		}
+	}
+	r.err = err
+	return c
+}
+
+// rollback 注销本次新注册的指标，避免部分注册
+func (r *registrar) rollback() {
+	for _, c := range r.added {
+		r.reg.Unregister(c)
+	}
+}
+
+// RegisterMetrics 在 engine 上注册指标中间件，并在 engine 或独立端口上暴露指标
+// 注册失败（如 label 与已注册指标冲突、端口绑定失败）时返回错误，不关心错误处理时使用 MustRegisterMetrics
+func RegisterMetrics(engine *gin.Engine, opts ...MetricsOption) (*Metrics, error) {
+	m, err := NewMetrics(opts...)
+	if err != nil {
+		return nil, err
+	}
+
+	engine.Use(m.Middleware())
+
+	if m.cfg.addr == "" {
+		engine.GET(m.cfg.path, gin.WrapH(m.Handler()))
+		return m, nil
+	}
+
+	if err = m.Start(); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+// MustRegisterMetrics 同 RegisterMetrics，失败时 panic（启动阶段暴露错误）
+func MustRegisterMetrics(engine *gin.Engine, opts ...MetricsOption) *Metrics {
+	m, err := RegisterMetrics(engine, opts...)
+	if err != nil {
+		panic(err)
+	}
+	return m
+}
+
+// Middleware 指标采集中间件
+func (m *Metrics) Middleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if m.cfg.addr == "" && c.FullPath() == m.cfg.path {
+			return
+		}
+
+		if m.inFlight != nil {
+			m.inFlight.Inc()
+			defer m.inFlight.Dec()
+		}
 
 		start := time.Now()
 		c.Next()
 
+		values := m.cfg.labelValues(c)
 		//记录请求次数
-		httpRequestsTotal.WithLabelValues(c.Request.Method, c.Request.URL.Path).Inc()
-		//记录http方法和路径对应的耗时
-		httpRequestDurationHistogram.WithLabelValues(c.Request.Method, c.Request.URL.Path).Observe(time.Since(start).Seconds())
-	})
+		m.requestsTotal.WithLabelValues(values...).Inc()
+		//记录http方法和路由模板对应的耗时
+		m.requestDuration.WithLabelValues(values...).Observe(time.Since(start).Seconds())
 
-	engine.GET("/metrics", gin.WrapH(promhttp.Handler()))
+		if m.requestSize != nil {
+			m.requestSize.WithLabelValues(values...).Observe(float64(max(c.Request.ContentLength, 0)))
+			m.responseSize.WithLabelValues(values...).Observe(float64(max(c.Writer.Size(), 0)))
+		}
+	}
+}
+
+// IncPanic 记录一次 handler panic，route 为空时使用未匹配占位符
+func (m *Metrics) IncPanic(route string) {
+	if route == "" {
+		route = m.cfg.unmatchedPath
+	}
+	m.panicsTotal.WithLabelValues(route).Inc()
+}
+
+// Handler 指标暴露 handler（已按配置启用 basic auth）
+func (m *Metrics) Handler() http.Handler {
+	h := promhttp.HandlerFor(m.cfg.gatherer, promhttp.HandlerOpts{})
+	if m.cfg.registerer == prometheus.DefaultRegisterer {
+		// 默认 registry 保持与 promhttp.Handler 一致的自监控指标
+		h = promhttp.InstrumentMetricHandler(m.cfg.registerer, h)
+	}
+	if len(m.cfg.basicAuth) > 0 {
+		h = basicAuth(m.cfg.basicAuth, h)
+	}
+	return h
+}
+
+// Start 在 WithMetricsAddr 指定的独立端口上暴露指标
+func (m *Metrics) Start() error {
+	if m.cfg.addr == "" {
+		return errors.New("metrics addr not configured")
+	}
+	if m.server != nil {
+		return nil
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle(m.cfg.path, m.Handler())
+
+	srv, err := startServer(m.cfg.addr, mux)
+	if err != nil {
+		return err
+	}
+	m.server = srv
+	return nil
+}
+
+// Addr 独立端口的实际监听地址
+func (m *Metrics) Addr() string {
+	return m.server.Addr()
+}
+
+// Shutdown 关闭独立端口的指标服务
+func (m *Metrics) Shutdown(ctx context.Context) error {
+	return m.server.Shutdown(ctx)
+}
+
+// labelValues 按 label 白名单顺序生成 label 值
+func (cfg *metricsConfig) labelValues(c *gin.Context) []string {
+	values := make([]string, len(cfg.labels))
+	for i, l := range cfg.labels {
+		switch l {
+		case LabelMethod:
+			values[i] = c.Request.Method
+		case LabelPath:
+			values[i] = routePath(c, cfg.unmatchedPath)
+		case LabelStatus:
+			values[i] = statusClass(c.Writer.Status())
+		}
+	}
+	return values
+}
+
+// routePath 返回路由模板（如 /users/:id），未匹配时返回占位符
+func routePath(c *gin.Context, unmatched string) string {
+	if p := c.FullPath(); p != "" {
+		return p
+	}
+	return unmatched
+}
+
+// statusClass 将状态码归类为 1xx~5xx
+func statusClass(status int) string {
+	if status < 100 || status > 599 {
+		return "unknown"
+	}
+	return strconv.Itoa(status/100) + "xx"
+}
+
+// basicAuth 校验 basic auth，使用常量时间比较
+func basicAuth(accounts map[string]string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if ok {
+			if expected, found := accounts[user]; found &&
+				subtle.ConstantTimeCompare([]byte(pass), []byte(expected)) == 1 {
+				next.ServeHTTP(w, r)
+				return
+			}
+		}
+		w.Header().Set("WWW-Authenticate", `Basic realm="metrics"`)
+		http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+	})
 }
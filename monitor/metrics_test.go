@@ -0,0 +1,153 @@
+package monitor
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMetrics(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	newEngine := func(t *testing.T, opts ...MetricsOption) (*gin.Engine, *prometheus.Registry) {
+		reg := prometheus.NewRegistry()
+		engine := gin.New()
+		_, err := RegisterMetrics(engine, append([]MetricsOption{WithRegisterer(reg)}, opts...)...)
+		require.NoError(t, err)
+		engine.GET("/users/:id", func(c *gin.Context) { c.String(http.StatusOK, "ok") })
+		return engine, reg
+	}
+
+	serve := func(engine *gin.Engine, method, path string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		engine.ServeHTTP(w, httptest.NewRequest(method, path, nil))
+		return w
+	}
+
+	t.Run("RouteTemplateLabels", func(t *testing.T) {
+		engine, reg := newEngine(t)
+		serve(engine, http.MethodGet, "/users/1")
+		serve(engine, http.MethodGet, "/users/2")
+		serve(engine, http.MethodGet, "/not-exist-1")
+		serve(engine, http.MethodGet, "/not-exist-2")
+
+		expected := `
+# HELP http_requests_total Total number of HTTP requests
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/users/:id",status="2xx"} 2
+http_requests_total{method="GET",path="<unmatched>",status="4xx"} 2
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total"))
+	})
+
+	t.Run("MultipleEngines", func(t *testing.T) {
+		// 不同 registry 上的 engine 各自计数，互不影响
+		e1, reg1 := newEngine(t)
+		e2, reg2 := newEngine(t)
+		serve(e1, http.MethodGet, "/users/1")
+		serve(e2, http.MethodGet, "/users/1")
+		serve(e2, http.MethodGet, "/users/2")
+
+		tmpl := `
+# HELP http_requests_total Total number of HTTP requests
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/users/:id",status="2xx"} %d
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg1, strings.NewReader(fmt.Sprintf(tmpl, 1)), "http_requests_total"))
+		assert.NoError(t, testutil.GatherAndCompare(reg2, strings.NewReader(fmt.Sprintf(tmpl, 2)), "http_requests_total"))
+	})
+
+	t.Run("MustRegisterPanics", func(t *testing.T) {
+		reg := prometheus.NewRegistry()
+		MustRegisterMetrics(gin.New(), WithRegisterer(reg))
+		assert.Panics(t, func() {
+			MustRegisterMetrics(gin.New(), WithRegisterer(reg), WithLabels(LabelPath))
+		})
+	})
+
+	t.Run("RegisterTwice", func(t *testing.T) {
+		// 同一 registry 上重复注册时复用已有指标，两个 engine 的请求计入同一序列
+		reg := prometheus.NewRegistry()
+		engines := make([]*gin.Engine, 2)
+		for i := range engines {
+			engines[i] = gin.New()
+			_, err := RegisterMetrics(engines[i], WithRegisterer(reg), WithInFlight())
+			require.NoError(t, err)
+			engines[i].GET("/ping", func(c *gin.Context) { c.Status(http.StatusNoContent) })
+			serve(engines[i], http.MethodGet, "/ping")
+		}
+		expected := `
+# HELP http_requests_total Total number of HTTP requests
+# TYPE http_requests_total counter
+http_requests_total{method="GET",path="/ping",status="2xx"} 2
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total"))
+
+		// label 不一致时报错并回滚本次新注册的指标
+		_, err := NewMetrics(WithRegisterer(reg), WithLabels(LabelPath), WithSizeMetrics())
+		require.Error(t, err)
+		n, err := testutil.GatherAndCount(reg, "http_request_size_bytes")
+		require.NoError(t, err)
+		assert.Zero(t, n)
+	})
+
+	t.Run("StatusClassAndUnmatched", func(t *testing.T) {
+		engine, reg := newEngine(t, WithLabels(LabelPath, LabelStatus), WithUnmatchedPath("other"))
+		engine.GET("/fail", func(c *gin.Context) { c.Status(http.StatusBadGateway) })
+		engine.GET("/redirect", func(c *gin.Context) { c.Redirect(http.StatusFound, "/users/1") })
+		serve(engine, http.MethodGet, "/fail")
+		serve(engine, http.MethodGet, "/redirect")
+		serve(engine, http.MethodGet, "/users/x/y")
+		serve(engine, http.MethodGet, "/metrics")
+
+		expected := `
+# HELP http_requests_total Total number of HTTP requests
+# TYPE http_requests_total counter
+http_requests_total{path="/fail",status="5xx"} 1
+http_requests_total{path="/redirect",status="3xx"} 1
+http_requests_total{path="other",status="4xx"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "http_requests_total"))
+
+		for status, want := range map[int]string{100: "1xx", 204: "2xx", 499: "4xx", 599: "5xx", 0: "unknown", 600: "unknown"} {
+			assert.Equal(t, want, statusClass(status), status)
+		}
+	})
+
+	t.Run("LabelAllowlistAndNamespace", func(t *testing.T) {
+		engine, reg := newEngine(t, WithLabels(LabelPath), WithNamespace("app"), WithInFlight(), WithSizeMetrics())
+		serve(engine, http.MethodPost, "/users/1")
+		serve(engine, http.MethodGet, "/users/1")
+
+		expected := `
+# HELP app_http_requests_total Total number of HTTP requests
+# TYPE app_http_requests_total counter
+app_http_requests_total{path="/users/:id"} 1
+app_http_requests_total{path="<unmatched>"} 1
+`
+		assert.NoError(t, testutil.GatherAndCompare(reg, strings.NewReader(expected), "app_http_requests_total"))
+
+		n, err := testutil.GatherAndCount(reg, "app_http_requests_in_flight", "app_http_response_size_bytes")
+		require.NoError(t, err)
+		assert.Equal(t, 3, n)
+	})
+
+	t.Run("BasicAuth", func(t *testing.T) {
+		engine, _ := newEngine(t, WithBasicAuth("admin", "secret"))
+		assert.Equal(t, http.StatusUnauthorized, serve(engine, http.MethodGet, "/metrics").Code)
+
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		req.SetBasicAuth("admin", "secret")
+		engine.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -1,18 +1,178 @@
 package monitor
 
 import (
+	"context"
+	"crypto/subtle"
+	"fmt"
+	"net"
 	"net/http"
-	_ "net/http/pprof"
+	"net/http/pprof"
+	"net/netip"
+	"strings"
 
 	"github.com/felixge/fgprof"
 )
 
-func StartPprof() {
-	http.DefaultServeMux.Handle("/debug/fgprof", fgprof.Handler())
+const defaultPprofAddr = ":6060"
 
-	go func() {
-		if err := http.ListenAndServe(":6060", nil); err != nil {
-			panic("go profiler server start error: " + err.Error())
+type pprofConfig struct {
+	addr     string
+	token    string
+	allowIPs []string
+}
+
+type PprofOption func(*pprofConfig)
+
+func defaultPprofConfig() *pprofConfig {
+	return &pprofConfig{
+		addr: defaultPprofAddr,
+	}
+}
+
+// WithPprofAddr 设置监听地址，默认 :6060
+func WithPprofAddr(addr string) PprofOption {
+	return func(cfg *pprofConfig) {
+		if addr != "" {
+			cfg.addr = addr
+		}
+	}
+}
+
+// WithPprofToken 要求请求携带 token（Header: Authorization: Bearer <token> 或 ?token=<token>）
+func WithPprofToken(token string) PprofOption {
+	return func(cfg *pprofConfig) {
+		cfg.token = token
+	}
+}
+
+// WithPprofAllowIPs 仅允许指定 IP 或 CIDR 访问，如 127.0.0.1、10.0.0.0/8
+func WithPprofAllowIPs(ips ...string) PprofOption {
+	return func(cfg *pprofConfig) {
+		cfg.allowIPs = append(cfg.allowIPs, ips...)
+	}
+}
+
+// Pprof 独立端口的 pprof/fgprof 服务
+type Pprof struct {
+	server *server
+}
+
+// StartPprof 启动 pprof/fgprof 服务，端口绑定失败时返回错误
+// 引用本包会导致 http.DefaultServeMux 上注册 pprof 路由，见 newPprofHandler
+func StartPprof(opts ...PprofOption) (*Pprof, error) {
+	cfg := defaultPprofConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	h, err := newPprofHandler(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	srv, err := startServer(cfg.addr, h)
+	if err != nil {
+		return nil, fmt.Errorf("go profiler server start error: %w", err)
+	}
+	return &Pprof{server: srv}, nil
+}
+
+// Addr 实际监听地址
+func (p *Pprof) Addr() string {
+	return p.server.Addr()
+}
+
+// Shutdown 优雅关闭 pprof 服务
+func (p *Pprof) Shutdown(ctx context.Context) error {
+	return p.server.Shutdown(ctx)
+}
+
+// newPprofHandler 使用独立 mux 注册 pprof/fgprof，鉴权与 IP 白名单仅作用于该 mux
+//
+// 注意：导入 net/http/pprof 会在 init 时向 http.DefaultServeMux 注册 /debug/pprof/*，
+// 引用本包即会生效。业务服务不应以 DefaultServeMux（或 http.ListenAndServe(addr, nil)）
+// 对外提供服务，否则 pprof 将绕过此处的鉴权直接暴露
+func newPprofHandler(cfg *pprofConfig) (http.Handler, error) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/pprof/", pprof.Index)
+	mux.HandleFunc("/debug/pprof/cmdline", pprof.Cmdline)
+	mux.HandleFunc("/debug/pprof/profile", pprof.Profile)
+	mux.HandleFunc("/debug/pprof/symbol", pprof.Symbol)
+	mux.HandleFunc("/debug/pprof/trace", pprof.Trace)
+	mux.Handle("/debug/fgprof", fgprof.Handler())
+
+	return cfg.protect(mux)
+}
+
+// protect 按配置的 token 与 IP 白名单包装 handler
+func (cfg *pprofConfig) protect(h http.Handler) (http.Handler, error) {
+	if cfg.token != "" {
+		h = tokenAuth(cfg.token, h)
+	}
+	if len(cfg.allowIPs) > 0 {
+		prefixes, err := parsePrefixes(cfg.allowIPs)
+		if err != nil {
+			return nil, err
+		}
+		h = ipAllowlist(prefixes, h)
+	}
+	return h, nil
+}
+
+// tokenAuth 校验访问 token
+func tokenAuth(token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		got := r.URL.Query().Get("token")
+		if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+			got = strings.TrimPrefix(auth, "Bearer ")
+		}
+		if subtle.ConstantTimeCompare([]byte(got), []byte(token)) != 1 {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+// ipAllowlist 按对端 IP 过滤请求（不信任 X-Forwarded-For）
+func ipAllowlist(prefixes []netip.Prefix, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		host, _, err := net.SplitHostPort(r.RemoteAddr)
+		if err != nil {
+			host = r.RemoteAddr
+		}
+		addr, err := netip.ParseAddr(host)
+		if err == nil {
+			addr = addr.Unmap()
+			for _, p := range prefixes {
+				if p.Contains(addr) {
+					next.ServeHTTP(w, r)
+					return
+				}
+			}
+		}
+		http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+	})
+}
+
+// parsePrefixes 解析 IP 或 CIDR 列表
+func parsePrefixes(ips []string) ([]netip.Prefix, error) {
+	prefixes := make([]netip.Prefix, 0, len(ips))
+	for _, s := range ips {
+		if strings.Contains(s, "/") {
+			p, err := netip.ParsePrefix(s)
+			if err != nil {
+				return nil, fmt.Errorf("invalid allow ip %q: %w", s, err)
+			}
+			prefixes = append(prefixes, p.Masked())
+			continue
+		}
+		addr, err := netip.ParseAddr(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid allow ip %q: %w", s, err)
 		}
-	}()
+		addr = addr.Unmap()
+		prefixes = append(prefixes, netip.PrefixFrom(addr, addr.BitLen()))
+	}
+	return prefixes, nil
 }
@@ -0,0 +1,93 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPprof(t *testing.T) {
+	ok := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) { w.WriteHeader(http.StatusOK) })
+
+	serve := func(h http.Handler, req *http.Request) int {
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	t.Run("TokenAuth", func(t *testing.T) {
+		h := tokenAuth("s3cret", ok)
+
+		assert.Equal(t, http.StatusUnauthorized, serve(h, httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)))
+		assert.Equal(t, http.StatusOK, serve(h, httptest.NewRequest(http.MethodGet, "/debug/pprof/?token=s3cret", nil)))
+		assert.Equal(t, http.StatusUnauthorized, serve(h, httptest.NewRequest(http.MethodGet, "/debug/pprof/?token=wrong", nil)))
+
+		req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+		req.Header.Set("Authorization", "Bearer s3cret")
+		assert.Equal(t, http.StatusOK, serve(h, req))
+
+		// Header 优先于 query
+		req = httptest.NewRequest(http.MethodGet, "/debug/pprof/?token=s3cret", nil)
+		req.Header.Set("Authorization", "Bearer wrong")
+		assert.Equal(t, http.StatusUnauthorized, serve(h, req))
+	})
+
+	t.Run("IPAllowlist", func(t *testing.T) {
+		prefixes, err := parsePrefixes([]string{"127.0.0.1", "10.1.2.3/8", "::ffff:192.168.1.1", "2001:db8::/32"})
+		require.NoError(t, err)
+		assert.Equal(t, "10.0.0.0/8", prefixes[1].String())
+		assert.Equal(t, "192.168.1.1/32", prefixes[2].String())
+		h := ipAllowlist(prefixes, ok)
+
+		for addr, want := range map[string]int{
+			"127.0.0.1:1234":         http.StatusOK,
+			"10.200.0.1:1234":        http.StatusOK,
+			"[::ffff:10.0.0.1]:1234": http.StatusOK,
+			"192.168.1.1:1234":       http.StatusOK,
+			"[2001:db8::1]:1234":     http.StatusOK,
+			"192.168.1.2:1234":       http.StatusForbidden,
+			"[::1]:1234":             http.StatusForbidden,
+			"not-an-ip":              http.StatusForbidden,
+			"[::ffff:11.0.0.1]:1234": http.StatusForbidden,
+		} {
+			req := httptest.NewRequest(http.MethodGet, "/debug/pprof/", nil)
+			req.RemoteAddr = addr
+			assert.Equal(t, want, serve(h, req), addr)
+		}
+
+		for _, bad := range []string{"localhost", "10.0.0.0/33", "1.2.3"} {
+			_, err := parsePrefixes([]string{bad})
+			assert.Error(t, err, bad)
+		}
+		_, err = StartPprof(WithPprofAddr("127.0.0.1:0"), WithPprofAllowIPs("bad-ip"))
+		assert.Error(t, err)
+	})
+
+	t.Run("StartAndShutdown", func(t *testing.T) {
+		p, err := StartPprof(WithPprofAddr("127.0.0.1:0"), WithPprofToken("s3cret"), WithPprofAllowIPs("127.0.0.1"))
+		require.NoError(t, err)
+
+		get := func(url string) int {
+			resp, err := http.Get(url)
+			require.NoError(t, err)
+			_ = resp.Body.Close()
+			return resp.StatusCode
+		}
+		base := "http://" + p.Addr() + "/debug/pprof/"
+		assert.Equal(t, http.StatusUnauthorized, get(base))
+		assert.Equal(t, http.StatusOK, get(base+"?token=s3cret"))
+
+		// 端口被占用时同步返回错误
+		_, err = StartPprof(WithPprofAddr(p.Addr()))
+		assert.Error(t, err)
+
+		require.NoError(t, p.Shutdown(context.Background()))
+		_, err = net.Dial("tcp", p.Addr())
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,617 @@
+package monitor
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/felixge/fgprof"
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/prometheus/client_golang/prometheus"
+	dto "github.com/prometheus/client_model/go"
+)
+
+// ProfileType 采集的 profile 类型
+type ProfileType string
+
+const (
+	ProfileCPU       ProfileType = "cpu"
+	ProfileHeap      ProfileType = "heap"
+	ProfileGoroutine ProfileType = "goroutine"
+	ProfileFgprof    ProfileType = "fgprof"
+)
+
+// 触发采集的原因，写入文件名
+const (
+	ReasonManual    = "manual"
+	ReasonSchedule  = "schedule"
+	ReasonCPU       = "cpu"
+	ReasonGoroutine = "goroutine"
+	ReasonLatency   = "latency"
+)
+
+const (
+	profileExt = ".pprof"
+	// minLatencySamples 一个检查周期内请求数过少时不计算 p99，避免噪声触发
+	minLatencySamples = 20
+)
+
+// ErrCaptureInProgress 已有采集正在进行
+var ErrCaptureInProgress = errors.New("profile capture in progress")
+
+type profilerConfig struct {
+	dir      string
+	types    []ProfileType
+	duration time.Duration // cpu/fgprof 采样时长
+
+	interval      time.Duration // 定时采集间隔，0 表示关闭
+	checkInterval time.Duration // 触发条件检查间隔
+	cooldown      time.Duration // 触发采集的最小间隔
+
+	maxFiles int
+	maxAge   time.Duration
+
+	cpuThreshold     float64 // 0~1，相对 GOMAXPROCS 的 CPU 使用率
+	goroutineDelta   int
+	latencyMetrics   *Metrics
+	latencyThreshold time.Duration
+
+	access *pprofConfig // Handler 的访问控制
+}
+
+type ProfilerOption func(*profilerConfig)
+
+func defaultProfilerConfig() *profilerConfig {
+	return &profilerConfig{
+		dir:           filepath.Join(os.TempDir(), "profiles"),
+		types:         []ProfileType{ProfileCPU, ProfileHeap, ProfileGoroutine, ProfileFgprof},
+		duration:      10 * time.Second,
+		checkInterval: 10 * time.Second,
+		cooldown:      5 * time.Minute,
+		maxFiles:      100,
+		maxAge:        7 * 24 * time.Hour,
+	}
+}
+
+// WithProfileDir 设置 profile 存放目录
+func WithProfileDir(dir string) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		if dir != "" {
+			cfg.dir = dir
+		}
+	}
+}
+
+// WithProfileTypes 设置采集的 profile 类型，默认全部
+func WithProfileTypes(types ...ProfileType) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		if len(types) > 0 {
+			cfg.types = types
+		}
+	}
+}
+
+// WithProfileDuration 设置 cpu/fgprof 采样时长，默认 10s
+func WithProfileDuration(d time.Duration) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		if d > 0 {
+			cfg.duration = d
+		}
+	}
+}
+
+// WithProfileInterval 启用定时采集
+func WithProfileInterval(d time.Duration) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		cfg.interval = d
+	}
+}
+
+// WithCheckInterval 设置触发条件检查间隔，默认 10s
+func WithCheckInterval(d time.Duration) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		if d > 0 {
+			cfg.checkInterval = d
+		}
+	}
+}
+
+// WithProfileCooldown 设置触发采集的最小间隔，默认 5m
+func WithProfileCooldown(d time.Duration) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		cfg.cooldown = d
+	}
+}
+
+// WithProfileRetention 设置保留的最大文件数与最长保留时间，<=0 表示不限制
+func WithProfileRetention(maxFiles int, maxAge time.Duration) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		cfg.maxFiles = maxFiles
+		cfg.maxAge = maxAge
+	}
+}
+
+// WithCPUTrigger CPU 使用率（0~1，相对 GOMAXPROCS）超过阈值时触发采集
+func WithCPUTrigger(threshold float64) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		cfg.cpuThreshold = threshold
+	}
+}
+
+// WithGoroutineTrigger 单个检查周期内 goroutine 数增长超过 delta 时触发采集
+func WithGoroutineTrigger(delta int) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		cfg.goroutineDelta = delta
+	}
+}
+
+// WithLatencyTrigger 检查周期内 HTTP 请求耗时 p99 超过阈值时触发采集
+func WithLatencyTrigger(m *Metrics, p99 time.Duration) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		cfg.latencyMetrics = m
+		cfg.latencyThreshold = p99
+	}
+}
+
+// WithProfileAccess 使用 WithPprofToken、WithPprofAllowIPs 限制 Handler 的访问，建议对外暴露时启用
+func WithProfileAccess(opts ...PprofOption) ProfilerOption {
+	return func(cfg *profilerConfig) {
+		if cfg.access == nil {
+			cfg.access = defaultPprofConfig()
+		}
+		for _, opt := range opts {
+			opt(cfg.access)
+		}
+	}
+}
+
+// ProfileFile 已采集的 profile 文件
+type ProfileFile struct {
+	Name    string    `json:"name"`
+	Size    int64     `json:"size"`
+	ModTime time.Time `json:"mod_time"`
+}
+
+// Profiler 持续 profiling：定时或按触发条件采集 profile 并写入本地目录
+type Profiler struct {
+	cfg *profilerConfig
+	seq atomic.Uint64 // 文件名序号，避免同一时刻的采集相互覆盖
+
+	// 便于测试替换
+	now          func() time.Time
+	cpuTime      func() (time.Duration, bool)
+	numGoroutine func() int
+
+	capturing   atomic.Bool
+	mu          sync.Mutex
+	lastCapture time.Time
+
+	// 触发条件状态，仅在后台循环中访问
+	lastCPU        time.Duration
+	lastCPUAt      time.Time
+	lastGoroutines int
+	lastLatency    histogramSnapshot
+
+	started  atomic.Bool
+	stopOnce sync.Once
+	stop     chan struct{}
+	done     chan struct{}
+}
+
+// NewProfiler 创建 Profiler，并确保 profile 目录存在
+func NewProfiler(opts ...ProfilerOption) (*Profiler, error) {
+	cfg := defaultProfilerConfig()
+	for _, opt := range opts {
+		opt(cfg)
+	}
+
+	if err := os.MkdirAll(cfg.dir, 0o755); err != nil {
+		return nil, fmt.Errorf("create profile dir failed: %w", err)
+	}
+
+	p := &Profiler{
+		cfg:          cfg,
+		now:          time.Now,
+		cpuTime:      processCPUTime,
+		numGoroutine: runtime.NumGoroutine,
+		stop:         make(chan struct{}),
+		done:         make(chan struct{}),
+	}
+	if cfg.access != nil {
+		if _, err := cfg.access.protect(http.NotFoundHandler()); err != nil {
+			return nil, err
+		}
+	}
+	return p, nil
+}
+
+// Start 启动后台定时采集与触发检查
+func (p *Profiler) Start() {
+	if p.started.CompareAndSwap(false, true) {
+		p.resetTriggers()
+		go p.loop()
+	}
+}
+
+// Shutdown 停止后台采集，等待进行中的采集结束
+func (p *Profiler) Shutdown(ctx context.Context) error {
+	p.stopOnce.Do(func() { close(p.stop) })
+	if !p.started.Load() {
+		return nil
+	}
+
+	select {
+	case <-p.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (p *Profiler) loop() {
+	defer close(p.done)
+
+	check := time.NewTicker(p.cfg.checkInterval)
+	defer check.Stop()
+
+	var schedule <-chan time.Time
+	if p.cfg.interval > 0 {
+		t := time.NewTicker(p.cfg.interval)
+		defer t.Stop()
+		schedule = t.C
+	}
+
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-schedule:
+			p.captureAndLog(ReasonSchedule)
+		case <-check.C:
+			p.checkAndCapture()
+		}
+	}
+}
+
+// checkAndCapture 检查触发条件，满足且已过冷却期时采集
+func (p *Profiler) checkAndCapture() {
+	if reason := p.checkTriggers(); reason != "" && p.cooledDown() {
+		p.captureAndLog(reason)
+	}
+}
+
+func (p *Profiler) captureAndLog(reason string) {
+	ctx := context.Background()
+	if err := p.Capture(reason); err != nil {
+		if !errors.Is(err, ErrCaptureInProgress) {
+			logging.L().Error(ctx).Err(err).Str("reason", reason).Msg("profile capture failed")
+		}
+		return
+	}
+	logging.L().Info(ctx).Str("reason", reason).Msg("profile captured")
+}
+
+func (p *Profiler) cooledDown() bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.lastCapture.IsZero() || p.now().Sub(p.lastCapture) >= p.cfg.cooldown
+}
+
+// resetTriggers 记录触发条件的基准值
+func (p *Profiler) resetTriggers() {
+	p.lastCPU, _ = p.cpuTime()
+	p.lastCPUAt = p.now()
+	p.lastGoroutines = p.numGoroutine()
+	if p.cfg.latencyMetrics != nil {
+		p.lastLatency = p.cfg.latencyMetrics.durationSnapshot()
+	}
+}
+
+// checkTriggers 检查触发条件，返回触发原因，未触发返回空串
+func (p *Profiler) checkTriggers() string {
+	reason := ""
+
+	if cpu, ok := p.cpuTime(); ok {
+		now := p.now()
+		wall := now.Sub(p.lastCPUAt)
+		if p.cfg.cpuThreshold > 0 && wall > 0 {
+			usage := float64(cpu-p.lastCPU) / float64(wall) / float64(runtime.GOMAXPROCS(0))
+			if usage >= p.cfg.cpuThreshold {
+				reason = ReasonCPU
+			}
+		}
+		p.lastCPU, p.lastCPUAt = cpu, now
+	}
+
+	n := p.numGoroutine()
+	if reason == "" && p.cfg.goroutineDelta > 0 && n-p.lastGoroutines >= p.cfg.goroutineDelta {
+		reason = ReasonGoroutine
+	}
+	p.lastGoroutines = n
+
+	if p.cfg.latencyMetrics != nil && p.cfg.latencyThreshold > 0 {
+		snap := p.cfg.latencyMetrics.durationSnapshot()
+		if reason == "" {
+			if p99, ok := snap.sub(p.lastLatency).quantile(0.99); ok && p99 >= p.cfg.latencyThreshold.Seconds() {
+				reason = ReasonLatency
+			}
+		}
+		p.lastLatency = snap
+	}
+
+	return reason
+}
+
+// Capture 立即采集一组 profile，cpu/fgprof 会阻塞采样时长
+func (p *Profiler) Capture(reason string) error {
+	if !p.capturing.CompareAndSwap(false, true) {
+		return ErrCaptureInProgress
+	}
+	defer p.capturing.Store(false)
+
+	prefix := fmt.Sprintf("%s_%04d_%s", p.now().Format("20060102T150405.000"), p.seq.Add(1), sanitizeName(reason))
+
+	var (
+		wg   sync.WaitGroup
+		mu   sync.Mutex
+		errs []error
+	)
+	addErr := func(err error) {
+		mu.Lock()
+		errs = append(errs, err)
+		mu.Unlock()
+	}
+
+	for _, typ := range p.cfg.types {
+		path := filepath.Join(p.cfg.dir, prefix+"_"+string(typ)+profileExt)
+		switch typ {
+		case ProfileHeap, ProfileGoroutine:
+			if err := p.writeSnapshot(path, string(typ)); err != nil {
+				addErr(err)
+			}
+		case ProfileCPU, ProfileFgprof:
+			wg.Add(1)
+			go func(typ ProfileType, path string) {
+				defer wg.Done()
+				if err := p.writeSampled(path, typ); err != nil {
+					addErr(err)
+				}
+			}(typ, path)
+		default:
+			addErr(fmt.Errorf("unknown profile type: %s", typ))
+		}
+	}
+	wg.Wait()
+
+	p.mu.Lock()
+	p.lastCapture = p.now()
+	p.mu.Unlock()
+
+	if err := p.prune(); err != nil {
+		errs = append(errs, err)
+	}
+	return errors.Join(errs...)
+}
+
+// writeSnapshot 写入 heap/goroutine 等即时 profile
+func (p *Profiler) writeSnapshot(path, name string) error {
+	prof := pprof.Lookup(name)
+	if prof == nil {
+		return fmt.Errorf("profile %s not found", name)
+	}
+	return writeFile(path, func(f *os.File) error {
+		return prof.WriteTo(f, 0)
+	})
+}
+
+// writeSampled 写入需要持续采样的 cpu/fgprof profile
+func (p *Profiler) writeSampled(path string, typ ProfileType) error {
+	return writeFile(path, func(f *os.File) error {
+		if typ == ProfileCPU {
+			if err := pprof.StartCPUProfile(f); err != nil {
+				return err
+			}
+			p.wait(p.cfg.duration)
+			pprof.StopCPUProfile()
+			return nil
+		}
+
+		stop := fgprof.Start(f, fgprof.FormatPprof)
+		p.wait(p.cfg.duration)
+		return stop()
+	})
+}
+
+// wait 等待采样时长，Shutdown 时提前结束
+func (p *Profiler) wait(d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-p.stop:
+	}
+}
+
+func writeFile(path string, write func(f *os.File) error) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return err
+	}
+	if err = write(f); err != nil {
+		_ = f.Close()
+		_ = os.Remove(path)
+		return err
+	}
+	return f.Close()
+}
+
+// List 按时间倒序列出已采集的 profile
+func (p *Profiler) List() ([]ProfileFile, error) {
+	entries, err := os.ReadDir(p.cfg.dir)
+	if err != nil {
+		return nil, err
+	}
+
+	files := make([]ProfileFile, 0, len(entries))
+	for _, e := range entries {
+		if e.IsDir() || filepath.Ext(e.Name()) != profileExt {
+			continue
+		}
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, ProfileFile{Name: e.Name(), Size: info.Size(), ModTime: info.ModTime()})
+	}
+
+	sort.Slice(files, func(i, j int) bool {
+		if files[i].ModTime.Equal(files[j].ModTime) {
+			return files[i].Name > files[j].Name
+		}
+		return files[i].ModTime.After(files[j].ModTime)
+	})
+	return files, nil
+}
+
+// prune 按保留策略清理旧文件
+func (p *Profiler) prune() error {
+	files, err := p.List()
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for i, f := range files {
+		expired := p.cfg.maxAge > 0 && p.now().Sub(f.ModTime) > p.cfg.maxAge
+		overflow := p.cfg.maxFiles > 0 && i >= p.cfg.maxFiles
+		if expired || overflow {
+			if err := os.Remove(filepath.Join(p.cfg.dir, f.Name)); err != nil && !os.IsNotExist(err) {
+				errs = append(errs, err)
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// Handler 管理端点：无参数时返回 profile 列表（JSON），?name=<file> 下载指定文件
+// 通过 WithProfileAccess 设置访问控制
+func (p *Profiler) Handler() http.Handler {
+	var h http.Handler = http.HandlerFunc(p.serve)
+	if p.cfg.access != nil {
+		h, _ = p.cfg.access.protect(h) // 配置已在 NewProfiler 中校验
+	}
+	return h
+}
+
+func (p *Profiler) serve(w http.ResponseWriter, r *http.Request) {
+	name := r.URL.Query().Get("name")
+	if name == "" {
+		files, err := p.List()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(files)
+		return
+	}
+
+	// 仅允许下载目录下的 profile 文件，防止路径穿越
+	if name != filepath.Base(name) || filepath.Ext(name) != profileExt {
+		http.Error(w, "invalid profile name", http.StatusBadRequest)
+		return
+	}
+	path := filepath.Join(p.cfg.dir, name)
+	if _, err := os.Stat(path); err != nil {
+		http.NotFound(w, r)
+		return
+	}
+	w.Header().Set("Content-Type", "application/octet-stream")
+	w.Header().Set("Content-Disposition", fmt.Sprintf("attachment; filename=%q", name))
+	http.ServeFile(w, r, path)
+}
+
+func sanitizeName(s string) string {
+	if s == "" {
+		return ReasonManual
+	}
+	return strings.Map(func(r rune) rune {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '-':
+			return r
+		default:
+			return '-'
+		}
+	}, s)
+}
+
+// histogramSnapshot 请求耗时直方图的累计值（所有 label 汇总）
+type histogramSnapshot struct {
+	count   uint64
+	buckets map[float64]uint64 // upper bound -> cumulative count
+}
+
+// durationSnapshot 汇总请求耗时直方图
+func (m *Metrics) durationSnapshot() histogramSnapshot {
+	snap := histogramSnapshot{buckets: make(map[float64]uint64)}
+
+	ch := make(chan prometheus.Metric, 64)
+	go func() {
+		m.requestDuration.Collect(ch)
+		close(ch)
+	}()
+
+	for metric := range ch {
+		var pb dto.Metric
+		if err := metric.Write(&pb); err != nil || pb.Histogram == nil {
+			continue
+		}
+		snap.count += pb.Histogram.GetSampleCount()
+		for _, b := range pb.Histogram.GetBucket() {
+			snap.buckets[b.GetUpperBound()] += b.GetCumulativeCount()
+		}
+	}
+	return snap
+}
+
+// sub 计算两次快照之间的增量
+func (s histogramSnapshot) sub(prev histogramSnapshot) histogramSnapshot {
+	delta := histogramSnapshot{count: s.count - prev.count, buckets: make(map[float64]uint64, len(s.buckets))}
+	for ub, c := range s.buckets {
+		delta.buckets[ub] = c - prev.buckets[ub]
+	}
+	return delta
+}
+
+// quantile 按 bucket 上界估算分位数（秒），超过最大 bucket 时返回 +Inf
+func (s histogramSnapshot) quantile(q float64) (float64, bool) {
+	if s.count < minLatencySamples {
+		return 0, false
+	}
+
+	bounds := make([]float64, 0, len(s.buckets))
+	for ub := range s.buckets {
+		bounds = append(bounds, ub)
+	}
+	sort.Float64s(bounds)
+
+	target := uint64(math.Ceil(q * float64(s.count)))
+	for _, ub := range bounds {
+		if s.buckets[ub] >= target {
+			return ub, true
+		}
+	}
+	return math.Inf(1), true
+}
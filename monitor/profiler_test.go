@@ -0,0 +1,179 @@
+package monitor
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProfiler(t *testing.T) {
+	p, err := NewProfiler(
+		WithProfileDir(t.TempDir()),
+		WithProfileTypes(ProfileHeap, ProfileGoroutine),
+		WithProfileRetention(2, time.Hour),
+	)
+	require.NoError(t, err)
+
+	t.Run("CaptureAndRetention", func(t *testing.T) {
+		require.NoError(t, p.Capture(ReasonManual))
+		require.NoError(t, p.Capture("high/load"))
+
+		files, err := p.List()
+		require.NoError(t, err)
+		assert.Len(t, files, 2)
+		for _, f := range files {
+			assert.NotContains(t, f.Name, "/")
+		}
+	})
+
+	t.Run("Handler", func(t *testing.T) {
+		h := p.Handler()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+
+		var files []ProfileFile
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &files))
+		require.NotEmpty(t, files)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?name="+files[0].Name, nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, int(files[0].Size), w.Body.Len())
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?name=../../etc/passwd", nil))
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Access", func(t *testing.T) {
+		p, err := NewProfiler(WithProfileDir(t.TempDir()), WithProfileAccess(WithPprofToken("s3cret")))
+		require.NoError(t, err)
+		h := p.Handler()
+
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/", nil))
+		assert.Equal(t, http.StatusUnauthorized, w.Code)
+
+		w = httptest.NewRecorder()
+		h.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/?token=s3cret", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+
+		_, err = NewProfiler(WithProfileDir(t.TempDir()), WithProfileAccess(WithPprofAllowIPs("bad-ip")))
+		assert.Error(t, err)
+	})
+
+	t.Run("LatencyQuantile", func(t *testing.T) {
+		snap := histogramSnapshot{count: 100, buckets: map[float64]uint64{0.1: 90, 1: 98, 5: 100}}
+		p99, ok := snap.quantile(0.99)
+		require.True(t, ok)
+		assert.Equal(t, 5.0, p99)
+
+		_, ok = histogramSnapshot{count: 1}.quantile(0.99)
+		assert.False(t, ok)
+	})
+}
+
+func TestProfilerTriggers(t *testing.T) {
+	newProfiler := func(t *testing.T, opts ...ProfilerOption) *Profiler {
+		opts = append([]ProfilerOption{WithProfileDir(t.TempDir()), WithProfileTypes(ProfileHeap), WithProfileRetention(0, 0)}, opts...)
+		p, err := NewProfiler(opts...)
+		require.NoError(t, err)
+		return p
+	}
+	count := func(t *testing.T, p *Profiler) int {
+		files, err := p.List()
+		require.NoError(t, err)
+		return len(files)
+	}
+
+	t.Run("SameTimeCaptures", func(t *testing.T) {
+		p := newProfiler(t)
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		p.now = func() time.Time { return now }
+
+		require.NoError(t, p.Capture(ReasonManual))
+		require.NoError(t, p.Capture(ReasonManual))
+		assert.Equal(t, 2, count(t, p))
+	})
+
+	t.Run("GoroutineCPUAndCooldown", func(t *testing.T) {
+		p := newProfiler(t, WithGoroutineTrigger(50), WithCPUTrigger(0.8), WithProfileCooldown(time.Minute))
+		now := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+		goroutines, cpu := 10, time.Duration(0)
+		p.now = func() time.Time { return now }
+		p.numGoroutine = func() int { return goroutines }
+		p.cpuTime = func() (time.Duration, bool) { return cpu, true }
+		p.resetTriggers()
+
+		tick := func(newGoroutines int, cpuUsage float64) {
+			now = now.Add(10 * time.Second)
+			goroutines += newGoroutines
+			cpu += time.Duration(cpuUsage * float64(10*time.Second) * float64(runtime.GOMAXPROCS(0)))
+		}
+
+		tick(10, 0.5)
+		assert.Empty(t, p.checkTriggers())
+		tick(50, 0.5)
+		assert.Equal(t, ReasonGoroutine, p.checkTriggers())
+		tick(0, 0.9)
+		assert.Equal(t, ReasonCPU, p.checkTriggers())
+
+		// 触发后采集，冷却期内不再采集
+		tick(50, 0)
+		p.checkAndCapture()
+		assert.Equal(t, 1, count(t, p))
+		tick(50, 0)
+		p.checkAndCapture()
+		assert.Equal(t, 1, count(t, p))
+
+		now = now.Add(time.Minute)
+		tick(50, 0)
+		p.checkAndCapture()
+		assert.Equal(t, 2, count(t, p))
+	})
+
+	t.Run("Latency", func(t *testing.T) {
+		m, err := NewMetrics(WithRegisterer(prometheus.NewRegistry()))
+		require.NoError(t, err)
+		p := newProfiler(t, WithLatencyTrigger(m, time.Second))
+		p.resetTriggers()
+
+		observe := func(n int, seconds float64) {
+			for range n {
+				m.requestDuration.WithLabelValues(http.MethodGet, "/users/:id", "2xx").Observe(seconds)
+			}
+		}
+
+		observe(minLatencySamples-1, 3)
+		assert.Empty(t, p.checkTriggers(), "样本过少不触发")
+		observe(100, 0.01)
+		assert.Empty(t, p.checkTriggers())
+		observe(30, 3)
+		assert.Equal(t, ReasonLatency, p.checkTriggers())
+	})
+
+	t.Run("PruneByAge", func(t *testing.T) {
+		p := newProfiler(t, WithProfileRetention(0, time.Hour))
+		old := filepath.Join(p.cfg.dir, "old_heap.pprof")
+		require.NoError(t, os.WriteFile(old, []byte("x"), 0o644))
+		require.NoError(t, os.Chtimes(old, time.Now().Add(-2*time.Hour), time.Now().Add(-2*time.Hour)))
+
+		require.NoError(t, p.Capture(ReasonManual))
+		files, err := p.List()
+		require.NoError(t, err)
+		require.Len(t, files, 1)
+		assert.NotEqual(t, "old_heap.pprof", files[0].Name)
+		assert.NoFileExists(t, old)
+	})
+}
@@ -0,0 +1,48 @@
+package monitor
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"time"
+)
+
+// server 独立端口的 HTTP 服务，启动失败同步返回，支持优雅关闭
+type server struct {
+	srv *http.Server
+}
+
+// startServer 监听 addr 并在后台提供服务，端口绑定失败时直接返回错误
+func startServer(addr string, handler http.Handler) (*server, error) {
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, err
+	}
+
+	srv := &http.Server{
+		Addr:              ln.Addr().String(),
+		Handler:           handler,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+	go func() {
+		_ = srv.Serve(ln) // Shutdown 后返回 http.ErrServerClosed
+	}()
+
+	return &server{srv: srv}, nil
+}
+
+// Addr 实际监听地址（addr 端口为 0 时可获取随机端口）
+func (s *server) Addr() string {
+	if s == nil {
+		return ""
+	}
+	return s.srv.Addr
+}
+
+// Shutdown 优雅关闭
+func (s *server) Shutdown(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	return s.srv.Shutdown(ctx)
+}
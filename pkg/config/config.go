@@ -6,10 +6,59 @@ import (
 	"path/filepath"
 	"strings"
 
+	"github.com/fsnotify/fsnotify"
 	"github.com/spf13/viper"
 )
 
 func Load[T any](configPath, configName, configType string) (*T, error) {
+	v, err := newViper(configPath, configName, configType)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg T
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal config to struct failed: %w", err)
+	}
+
+	return &cfg, nil
+}
+
+func LoadConf[T any](configFile string) (*T, error) {
+	return Load[T](splitConfigFile(configFile))
+}
+
+// Watch 加载配置并监听文件变更，变更后重新解析并回调 onChange（如调用 logging.ApplyLevels 调整日志级别）
+// 重新读取或解析失败时忽略本次变更，仅通过 onError 通知（可为 nil）
+func Watch[T any](configFile string, onChange func(*T), onError func(error)) (*T, error) {
+	configPath, configName, configType := splitConfigFile(configFile)
+	v, err := newViper(configPath, configName, configType)
+	if err != nil {
+		return nil, err
+	}
+
+	var cfg T
+	if err := v.Unmarshal(&cfg); err != nil {
+		return nil, fmt.Errorf("unmarshal config to struct failed: %w", err)
+	}
+
+	v.OnConfigChange(func(fsnotify.Event) {
+		// viper 读取失败时仅记录日志并保留旧配置，这里重新加载以便将错误交给 onError
+		changed, err := Load[T](configPath, configName, configType)
+		if err != nil {
+			if onError != nil {
+				onError(err)
+			}
+			return
+		}
+		onChange(changed)
+	})
+	v.WatchConfig()
+
+	return &cfg, nil
+}
+
+func newViper(configPath, configName, configType string) (*viper.Viper, error) {
 	v := viper.New()
 
 	// 1. 配置文件基础设置
@@ -30,19 +79,14 @@ func Load[T any](configPath, configName, configType string) (*T, error) {
 	v.AutomaticEnv()
 	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_", "-", "_"))
 
-	var cfg T
-	if err := v.Unmarshal(&cfg); err != nil {
-		return nil, fmt.Errorf("unmarshal config to struct failed: %w", err)
-	}
-
-	return &cfg, nil
+	return v, nil
 }
 
-func LoadConf[T any](configFile string) (*T, error) {
+func splitConfigFile(configFile string) (string, string, string) {
 	dir := filepath.Dir(configFile)
 	file := filepath.Base(configFile)
 	ext := filepath.Ext(file)
 	name := strings.TrimSuffix(file, ext)
 	configType := strings.TrimPrefix(ext, ".")
-	return Load[T](dir, name, configType)
+	return dir, name, configType
 }
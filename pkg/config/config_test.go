@@ -0,0 +1,62 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type watchConf struct {
+	Level string `mapstructure:"level"`
+	Port  int    `mapstructure:"port"`
+}
+
+func TestWatch(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "app.yaml")
+
+	// 先写临时文件再 rename，避免截断写入时触发读到空文件
+	write := func(content string) {
+		tmp := filepath.Join(dir, "app.tmp")
+		require.NoError(t, os.WriteFile(tmp, []byte(content), 0o644))
+		require.NoError(t, os.Rename(tmp, file))
+	}
+	write("level: info\nport: 8080\n")
+
+	changes := make(chan *watchConf, 8)
+	errs := make(chan error, 8)
+	cfg, err := Watch[watchConf](file, func(c *watchConf) { changes <- c }, func(err error) { errs <- err })
+	require.NoError(t, err)
+	assert.Equal(t, &watchConf{Level: "info", Port: 8080}, cfg)
+
+	t.Run("OnChange", func(t *testing.T) {
+		write("level: debug\nport: 9090\n")
+		select {
+		case c := <-changes:
+			assert.Equal(t, &watchConf{Level: "debug", Port: 9090}, c)
+		case <-time.After(3 * time.Second):
+			t.Fatal("onChange not called")
+		}
+	})
+
+	for name, content := range map[string]string{
+		"InvalidSyntax": "level: [debug\n",
+		"InvalidType":   "level: debug\nport: abc\n",
+	} {
+		t.Run(name, func(t *testing.T) {
+			write(content)
+			select {
+			case err := <-errs:
+				assert.Error(t, err)
+			case c := <-changes:
+				t.Fatalf("onChange called with %+v", c)
+			case <-time.After(3 * time.Second):
+				t.Fatal("onError not called")
+			}
+		})
+	}
+}
@@ -1,7 +1,6 @@
 package logging
 
 import (
-	"bufio"
 	"fmt"
 	"io"
 	"os"
@@ -9,7 +8,6 @@ import (
 	"strings"
 
 	"github.com/rs/zerolog"
-	"gopkg.in/natefinch/lumberjack.v2"
 )
 
 // 定义别名（抽象提取，可替换不同实现）
@@ -19,30 +17,49 @@ type (
 	Level  = zerolog.Level
 )
 
-func newZerolog(cfg *config) logger {
-	// 全局配置
-	zerolog.TimeFieldFormat = "2006-01-02 15:04:05.000Z07:00"
+// 日志级别
+const (
+	DebugLevel = zerolog.DebugLevel
+	InfoLevel  = zerolog.InfoLevel
+	WarnLevel  = zerolog.WarnLevel
+	ErrorLevel = zerolog.ErrorLevel
+	FatalLevel = zerolog.FatalLevel
+	PanicLevel = zerolog.PanicLevel
+	Disabled   = zerolog.Disabled // 关闭输出
+)
+
+func init() {
+	// zerolog 仅支持全局设置 caller 格式，进程内只设置一次
 	zerolog.CallerMarshalFunc = callerShortFunc
+}
 
-	base := zerolog.New(cfg.output).
-		Level(cfg.level).
-		With().
-		Timestamp().
-		Logger()
+func newZerolog(cfg *config, out io.Writer) logger {
+	// 级别由 LevelController 控制，底层不过滤
+	base := zerolog.New(out).
+		Level(zerolog.TraceLevel).
+		Hook(timestampHook(cfg.timeFormat))
 	return base
 }
 
 type config struct {
-	level  Level
-	output io.Writer
+	level       Level
+	levelConfig *LevelConfig
+	sinks       []Sink
+	format      string
+	timeFormat  string
+	noColor     bool
+	sample      *samplingConfig
+	mask        *maskConfig
 }
 
 type Option func(*config)
 
 func defaultConfig() *config {
 	return &config{
-		level:  zerolog.InfoLevel,
-		output: os.Stdout,
+		level:      zerolog.InfoLevel,
+		sinks:      []Sink{{Writer: os.Stdout}},
+		format:     FormatJSON,
+		timeFormat: DefaultTimeFormat,
 	}
 }
 
@@ -52,26 +69,24 @@ func WithLevel(level Level) Option {
 	}
 }
 
-func WithOutput(w io.Writer) Option {
+// WithLevels 按配置设置全局级别及组件级别覆盖，如 {"gorm": "warn", "payment": "debug"}，配置非法时 panic
+func WithLevels(cfg LevelConfig) Option {
 	return func(c *config) {
-		c.output = w
+		c.levelConfig = &cfg
 	}
 }
 
-func WithOutputFile(filepath string) Option {
+func WithOutput(w io.Writer) Option {
 	return func(c *config) {
-		lj := &lumberjack.Logger{
-			Filename:   filepath, // 路径
-			MaxSize:    200,      // 单个文件最大 MB
-			MaxBackups: 5,        // 最多保留几个旧文件
-			MaxAge:     14,       // 旧文件最长保存天数
-			Compress:   true,     // 是否 gzip 压缩旧文件
-		}
-		bufWriter := bufio.NewWriter(lj)
-		c.output = bufWriter
+		c.sinks = []Sink{{Writer: w}}
 	}
 }
 
+// WithOutputFile 异步写入滚动文件（默认 200MB、保留 5 个、14 天、压缩），退出前需调用 Close 刷出缓冲，队列满丢弃的行数见 Dropped
+func WithOutputFile(filepath string, opts ...AsyncOption) Option {
+	return WithFile(DefaultFileConfig(filepath), opts...)
+}
+
 func callerShortFunc(_ uintptr, file string, line int) string {
 	file = filepath.ToSlash(file)
 	parts := strings.Split(file, "/")
@@ -1,12 +1,43 @@
 package logging
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+
+	"github.com/rs/zerolog"
 )
 
 type ctxFieldsKey struct{}
 
-// WithFields 绑定 fields 到 ctx
+// ContextExtractor 在输出日志时从 ctx 动态提取 fields（如 trace_id、span_id）
+type ContextExtractor func(ctx context.Context) []Field
+
+var (
+	extractorsMu sync.RWMutex
+	extractors   []ContextExtractor
+)
+
+// RegisterContextExtractor 注册 ctx 字段提取器，对所有 Logger 生效
+func RegisterContextExtractor(fn ContextExtractor) {
+	if fn == nil {
+		return
+	}
+	extractorsMu.Lock()
+	extractors = append(extractors, fn)
+	extractorsMu.Unlock()
+}
+
+// ctxField 绑定到 ctx 的 field 及其 key，key 在绑定时渲染一次获得
+type ctxField struct {
+	key string
+	f   Field
+}
+
+// WithFields 绑定 fields 到 ctx，与已有 field 同 key 时覆盖原值（保持原有位置）
+// 每次返回新的 fields 副本，不影响父 ctx
 func WithFields(ctx context.Context, fields ...Field) context.Context {
 	if ctx == nil {
 		ctx = context.Background()
@@ -16,18 +47,149 @@ func WithFields(ctx context.Context, fields ...Field) context.Context {
 		return ctx
 	}
 
-	existing, _ := ctx.Value(ctxFieldsKey{}).([]Field)
-	all := append(existing, fields...)
+	existing, _ := ctx.Value(ctxFieldsKey{}).([]ctxField)
+	return context.WithValue(ctx, ctxFieldsKey{}, mergeFields(existing, keyFields(fields)))
+}
+
+// WithoutFields 从 ctx 中移除指定 key 的 fields，返回新的 ctx
+func WithoutFields(ctx context.Context, keys ...string) context.Context {
+	if ctx == nil {
+		return context.Background()
+	}
+
+	existing, _ := ctx.Value(ctxFieldsKey{}).([]ctxField)
+	if len(existing) == 0 || len(keys) == 0 {
+		return ctx
+	}
+
+	remain := make([]ctxField, 0, len(existing))
+	for _, cf := range existing {
+		if cf.key == "" || !slices.Contains(keys, cf.key) {
+			remain = append(remain, cf)
+		}
+	}
+	if len(remain) == len(existing) {
+		return ctx
+	}
+	return context.WithValue(ctx, ctxFieldsKey{}, remain)
+}
+
+func keyFields(fields []Field) []ctxField {
+	out := make([]ctxField, 0, len(fields))
+	for _, f := range fields {
+		if f != nil {
+			out = append(out, ctxField{key: fieldKey(f), f: f})
+		}
+	}
+	return out
+}
+
+// fieldKey 渲染 field，返回其写入的首个 key，未写入任何 key 时为空串（不参与去重）
+func fieldKey(f Field) string {
+	var buf bytes.Buffer
+	zl := zerolog.New(&buf)
+	e := zl.Log()
+	f(e)
+	e.Send()
 
-	return context.WithValue(ctx, ctxFieldsKey{}, all)
+	dec := json.NewDecoder(&buf)
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return ""
+	}
+	tok, err := dec.Token()
+	if err != nil {
+		return ""
+	}
+	key, _ := tok.(string)
+	return key
 }
 
-// FieldsFrom 获取 ctx 中绑定的 fields
+// mergeFields 返回新切片：同 key 的 field 由 overrides 覆盖，其余追加在末尾
+func mergeFields(base, overrides []ctxField) []ctxField {
+	all := make([]ctxField, len(base), len(base)+len(overrides))
+	copy(all, base)
+
+	index := make(map[string]int, len(all)+len(overrides))
+	for i, cf := range all {
+		if cf.key != "" {
+			index[cf.key] = i
+		}
+	}
+	for _, cf := range overrides {
+		if i, ok := index[cf.key]; ok && cf.key != "" {
+			all[i] = cf
+			continue
+		}
+		if cf.key != "" {
+			index[cf.key] = len(all)
+		}
+		all = append(all, cf)
+	}
+	return all
+}
+
+// FieldsFrom 获取 ctx 中绑定的 fields（包含提取器动态提取的 fields，同 key 时以提取器为准）
 func FieldsFrom(ctx context.Context) []Field {
 	if ctx == nil {
 		return nil
 	}
 
-	fields, _ := ctx.Value(ctxFieldsKey{}).([]Field)
+	bound, _ := ctx.Value(ctxFieldsKey{}).([]ctxField)
+	if extracted := extractFields(ctx); len(extracted) > 0 {
+		bound = mergeFields(bound, keyFields(extracted))
+	}
+	if len(bound) == 0 {
+		return nil
+	}
+
+	fields := make([]Field, len(bound))
+	for i, cf := range bound {
+		fields[i] = cf.f
+	}
 	return fields
 }
+
+// attachCtxFields 写入 ctx 绑定的 fields 及提取器提取的 fields，
+// 与事件字段或彼此间重复的 key 在输出时去重（保留最后写入的值）
+func attachCtxFields(ctx context.Context, e *Event) {
+	bound, _ := ctx.Value(ctxFieldsKey{}).([]ctxField)
+	for _, cf := range bound {
+		cf.f(e)
+	}
+	for _, f := range extractFields(ctx) {
+		f(e)
+	}
+}
+
+func extractFields(ctx context.Context) []Field {
+	extractorsMu.RLock()
+	defer extractorsMu.RUnlock()
+
+	var extracted []Field
+	for _, fn := range extractors {
+		extracted = append(extracted, fn(ctx)...)
+	}
+	return extracted
+}
+
+// FieldsMap 将 ctx 中的 fields 渲染为 key-value，便于跨进程传递
+func FieldsMap(ctx context.Context) map[string]any {
+	fields := FieldsFrom(ctx)
+	if len(fields) == 0 {
+		return nil
+	}
+
+	var buf bytes.Buffer
+	zl := zerolog.New(&buf)
+	e := zl.Log()
+	for _, f := range fields {
+		f(e)
+	}
+	e.Send()
+
+	m := make(map[string]any, len(fields))
+	if err := json.Unmarshal(buf.Bytes(), &m); err != nil {
+		return nil
+	}
+	return m
+}
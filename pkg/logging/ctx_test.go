@@ -0,0 +1,74 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWithFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf))
+
+	parent := WithFields(context.Background(), Str("request_id", "r1"), Str("field", "0000"), Int("n", 1))
+
+	t.Run("Override", func(t *testing.T) {
+		buf.Reset()
+		ctx := WithFields(parent, Str("field", "1111"), Str("field", "2222"))
+		l.Info(ctx).Msg("override")
+
+		out := buf.String()
+		assert.Equal(t, 1, strings.Count(out, `"field"`))
+		assert.Contains(t, out, `{"level":"info","request_id":"r1","field":"2222","n":1,`)
+		assert.Equal(t, map[string]any{"request_id": "r1", "field": "0000", "n": 1.0}, FieldsMap(parent), "不应影响父 ctx")
+	})
+
+	t.Run("CopyOnWrite", func(t *testing.T) {
+		a := WithFields(parent, Str("branch", "a"))
+		b := WithFields(parent, Str("branch", "b"))
+		assert.Equal(t, "a", FieldsMap(a)["branch"])
+		assert.Equal(t, "b", FieldsMap(b)["branch"])
+		assert.NotContains(t, FieldsMap(parent), "branch")
+	})
+
+	t.Run("Remove", func(t *testing.T) {
+		ctx := WithoutFields(parent, "field", "missing")
+		assert.Equal(t, map[string]any{"request_id": "r1", "n": 1.0}, FieldsMap(ctx))
+		assert.Len(t, FieldsFrom(parent), 3)
+		assert.Equal(t, parent, WithoutFields(parent, "missing"))
+	})
+
+	t.Run("ClosureField", func(t *testing.T) {
+		// 自定义闭包 Field 同样按写入的 key 覆盖与移除
+		var custom Field = func(e *Event) { e.Str("field", "custom") }
+		ctx := WithFields(parent, custom)
+		assert.Equal(t, "custom", FieldsMap(ctx)["field"])
+		assert.NotContains(t, FieldsMap(WithoutFields(ctx, "field")), "field")
+	})
+
+	t.Run("EventOverridesCtx", func(t *testing.T) {
+		buf.Reset()
+		l.Info(parent).Str("field", "event").EmbedObject(Dict("d", Str("field", "nested"))).Msg("dup")
+
+		out := buf.String()
+		assert.Equal(t, 1, strings.Count(out, `"field":"event"`))
+		assert.NotContains(t, out, `"field":"0000"`)
+		assert.Contains(t, out, `"field":"nested"`, "嵌套对象中的同名 key 不受影响")
+	})
+}
+
+func TestDedupKeys(t *testing.T) {
+	for in, want := range map[string]string{
+		`{"a":1,"b":"x","a":2}` + "\n":                 `{"b":"x","a":2}` + "\n",
+		`{"a":"q\"a\":","b":[1,{"a":3}],"c":{"a":{}}}`: `{"a":"q\"a\":","b":[1,{"a":3}],"c":{"a":{}}}`,
+		`{"k":true,"k":null,"k":"v"}`:                  `{"k":"v"}`,
+		`{}`:                                           `{}`,
+		`not json`:                                     `not json`,
+		`{"a":1,"a":`:                                  `{"a":1,"a":`,
+	} {
+		assert.Equal(t, want, string(dedupKeys([]byte(in))), in)
+	}
+}
@@ -0,0 +1,180 @@
+package logging
+
+import (
+	"bytes"
+	"io"
+
+	"github.com/rs/zerolog"
+)
+
+// dedupWriter 输出前移除日志行中重复的顶层 key（如 ctx field 与事件字段同名），保留最后写入的值
+type dedupWriter struct {
+	out io.Writer
+}
+
+func (w dedupWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w dedupWriter) WriteLevel(level Level, p []byte) (int, error) {
+	line := dedupKeys(p)
+
+	var err error
+	if lw, ok := w.out.(zerolog.LevelWriter); ok {
+		_, err = lw.WriteLevel(level, line)
+	} else {
+		_, err = w.out.Write(line)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w dedupWriter) Sync() error {
+	return syncWriter(w.out)
+}
+
+func (w dedupWriter) Close() error {
+	return closeWriter(w.out)
+}
+
+type keySpan struct {
+	key        []byte // 含引号的原始 key
+	start, end int    // key 起始至值结束
+}
+
+// dedupKeys 扫描 JSON 行的顶层 key，有重复时重建该行，否则（或无法解析时）原样返回
+func dedupKeys(line []byte) []byte {
+	var buf [24]keySpan
+	spans := buf[:0]
+
+	i := skipSpace(line, 0)
+	if i >= len(line) || line[i] != '{' {
+		return line
+	}
+	i = skipSpace(line, i+1)
+	if i < len(line) && line[i] == '}' {
+		return line
+	}
+	for {
+		if i >= len(line) || line[i] != '"' {
+			return line
+		}
+		start := i
+		i = skipString(line, i)
+		if i < 0 {
+			return line
+		}
+		key := line[start:i]
+		i = skipSpace(line, i)
+		if i >= len(line) || line[i] != ':' {
+			return line
+		}
+		i = skipValue(line, skipSpace(line, i+1))
+		if i < 0 {
+			return line
+		}
+		spans = append(spans, keySpan{key: key, start: start, end: i})
+
+		i = skipSpace(line, i)
+		if i >= len(line) {
+			return line
+		}
+		if line[i] == '}' {
+			break
+		}
+		if line[i] != ',' {
+			return line
+		}
+		i = skipSpace(line, i+1)
+	}
+
+	drop := make([]bool, 0, len(spans))
+	dup := false
+	for a := range spans {
+		d := false
+		for b := a + 1; b < len(spans); b++ {
+			if bytes.Equal(spans[a].key, spans[b].key) {
+				d, dup = true, true
+				break
+			}
+		}
+		drop = append(drop, d)
+	}
+	if !dup {
+		return line
+	}
+
+	out := make([]byte, 0, len(line))
+	out = append(out, line[:spans[0].start]...)
+	n := 0
+	for k, sp := range spans {
+		if drop[k] {
+			continue
+		}
+		if n > 0 {
+			out = append(out, ',')
+		}
+		out = append(out, line[sp.start:sp.end]...)
+		n++
+	}
+	return append(out, line[i:]...)
+}
+
+func skipSpace(b []byte, i int) int {
+	for i < len(b) && (b[i] == ' ' || b[i] == '\t' || b[i] == '\n' || b[i] == '\r') {
+		i++
+	}
+	return i
+}
+
+// skipString 返回字符串结束引号之后的位置，i 指向起始引号，未闭合时返回 -1
+func skipString(b []byte, i int) int {
+	for i++; i < len(b); i++ {
+		switch b[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return -1
+}
+
+// skipValue 返回值结束之后的位置，无法解析时返回 -1
+func skipValue(b []byte, i int) int {
+	if i >= len(b) {
+		return -1
+	}
+	switch b[i] {
+	case '"':
+		return skipString(b, i)
+	case '{', '[':
+		depth := 0
+		for ; i < len(b); i++ {
+			switch b[i] {
+			case '"':
+				if i = skipString(b, i); i < 0 {
+					return -1
+				}
+				i--
+			case '{', '[':
+				depth++
+			case '}', ']':
+				if depth--; depth == 0 {
+					return i + 1
+				}
+			}
+		}
+		return -1
+	default: // 数字、布尔、null
+		for ; i < len(b); i++ {
+			switch b[i] {
+			case ',', '}', ']', ' ', '\t', '\n', '\r':
+				return i
+			}
+		}
+		return i
+	}
+}
@@ -1,31 +1,146 @@
 package logging
 
 import (
+	"fmt"
+	"net"
 	"time"
+
+	"github.com/rs/zerolog"
 )
 
+// Field 日志字段，绑定到 ctx 时按写入的首个 key 去重覆盖
 type Field func(e *Event)
 
+// ObjectMarshaler 自定义对象的日志输出，配合 Object 使用
+type ObjectMarshaler = zerolog.LogObjectMarshaler
+
+// MarshalZerologObject 使 Field 可直接用于 Event，如 L().Info(ctx).EmbedObject(Str("k", "v"))
+func (f Field) MarshalZerologObject(e *Event) {
+	f(e)
+}
+
+// Fields 一组 Field，可直接用于 Event.EmbedObject
+type Fields []Field
+
+// MarshalZerologObject 依次写入全部 Field
+func (fs Fields) MarshalZerologObject(e *Event) {
+	for _, f := range fs {
+		f(e)
+	}
+}
+
+// Apply 将 fields 写入 Event，e 为 nil 时为空操作
+func Apply(e *Event, fields ...Field) *Event {
+	if e == nil {
+		return nil
+	}
+	for _, f := range fields {
+		f(e)
+	}
+	return e
+}
+
 // Str 返回字符串字段
 func Str(key, val string) Field {
 	return func(e *Event) { e.Str(key, val) }
 }
 
+// Strs 返回字符串数组字段
+func Strs(key string, vals []string) Field {
+	return func(e *Event) { e.Strs(key, vals) }
+}
+
+// Stringer 返回 fmt.Stringer 字段，val 为 nil 时输出 null
+func Stringer(key string, val fmt.Stringer) Field {
+	return func(e *Event) { e.Stringer(key, val) }
+}
+
+// Bool 返回布尔字段
+func Bool(key string, val bool) Field {
+	return func(e *Event) { e.Bool(key, val) }
+}
+
 // Int 返回整数字段
 func Int(key string, val int) Field {
 	return func(e *Event) { e.Int(key, val) }
 }
 
+// Ints 返回整数数组字段
+func Ints(key string, vals []int) Field {
+	return func(e *Event) { e.Ints(key, vals) }
+}
+
 // Int64 返回 int64 字段
 func Int64(key string, val int64) Field {
 	return func(e *Event) { e.Int64(key, val) }
 }
 
+// Uint 返回无符号整数字段
+func Uint(key string, val uint) Field {
+	return func(e *Event) { e.Uint(key, val) }
+}
+
+// Uint64 返回 uint64 字段
+func Uint64(key string, val uint64) Field {
+	return func(e *Event) { e.Uint64(key, val) }
+}
+
+// Float64 返回浮点数字段
+func Float64(key string, val float64) Field {
+	return func(e *Event) { e.Float64(key, val) }
+}
+
 // Dur 返回 duration 字段
 func Dur(key string, val time.Duration) Field {
 	return func(e *Event) { e.Dur(key, val) }
 }
 
+// Time 返回时间字段，格式为 RFC3339
+func Time(key string, val time.Time) Field {
+	return func(e *Event) { e.Time(key, val) }
+}
+
+// Bytes 返回字节字段，按字符串输出
+func Bytes(key string, val []byte) Field {
+	return func(e *Event) { e.Bytes(key, val) }
+}
+
+// Hex 返回字节字段，按十六进制输出
+func Hex(key string, val []byte) Field {
+	return func(e *Event) { e.Hex(key, val) }
+}
+
+// IP 返回 IP 地址字段
+func IP(key string, val net.IP) Field {
+	return func(e *Event) { e.IPAddr(key, val) }
+}
+
+// Any 返回任意类型字段，按 JSON 序列化输出
+func Any(key string, val any) Field {
+	return func(e *Event) { e.Interface(key, val) }
+}
+
+// Interface 同 Any
+func Interface(key string, val any) Field {
+	return Any(key, val)
+}
+
+// Dict 返回嵌套对象字段，如 Dict("user", Int("id", 1), Str("name", "tom"))
+func Dict(key string, fields ...Field) Field {
+	return func(e *Event) {
+		d := zerolog.Dict()
+		for _, f := range fields {
+			f(d)
+		}
+		e.Dict(key, d)
+	}
+}
+
+// Object 返回实现了 ObjectMarshaler 的对象字段
+func Object(key string, val ObjectMarshaler) Field {
+	return func(e *Event) { e.Object(key, val) }
+}
+
 // Err 返回错误字段
 func Err(err error) Field {
 	return func(e *Event) {
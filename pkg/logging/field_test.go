@@ -0,0 +1,68 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type testUser struct {
+	ID   int
+	Name string
+}
+
+func (u testUser) MarshalZerologObject(e *Event) {
+	e.Int("id", u.ID).Str("name", u.Name)
+}
+
+func TestFields(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf))
+
+	ts := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+	ctx := WithFields(context.Background(),
+		Bool("ok", true),
+		Float64("ratio", 0.5),
+		Uint("uid", 7),
+		Time("at", ts),
+		Strs("tags", []string{"a", "b"}),
+		Ints("ids", []int{1, 2}),
+		Any("meta", map[string]int{"x": 1}),
+		Bytes("raw", []byte("hi")),
+		Hex("sum", []byte{0xab}),
+		IP("ip", net.ParseIP("10.0.0.1")),
+		Dict("req", Str("method", "GET"), Int("size", 3)),
+		Object("user", testUser{ID: 1, Name: "tom"}),
+		Stringer("dur", time.Second),
+		Err(errors.New("boom")),
+	)
+	l.Info(ctx).EmbedObject(Str("extra", "v")).EmbedObject(Fields{Int("a", 1), Int("b", 2)}).Msg("fields")
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, true, m["ok"])
+	assert.Equal(t, 0.5, m["ratio"])
+	assert.EqualValues(t, 7, m["uid"])
+	assert.Equal(t, "2024-05-01T10:00:00Z", m["at"])
+	assert.Equal(t, []any{"a", "b"}, m["tags"])
+	assert.Equal(t, []any{1.0, 2.0}, m["ids"])
+	assert.Equal(t, map[string]any{"x": 1.0}, m["meta"])
+	assert.Equal(t, "hi", m["raw"])
+	assert.Equal(t, "ab", m["sum"])
+	assert.Equal(t, "10.0.0.1", m["ip"])
+	assert.Equal(t, map[string]any{"method": "GET", "size": 3.0}, m["req"])
+	assert.Equal(t, map[string]any{"id": 1.0, "name": "tom"}, m["user"])
+	assert.Equal(t, "1s", m["dur"])
+	assert.Equal(t, "boom", m["error"])
+	assert.Equal(t, "v", m["extra"])
+	assert.EqualValues(t, 2, m["b"])
+
+	assert.Nil(t, Apply(nil, Str("k", "v")))
+}
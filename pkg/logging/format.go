@@ -0,0 +1,189 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// 输出格式
+const (
+	FormatJSON    = "json"
+	FormatLogfmt  = "logfmt"
+	FormatConsole = "console" // 人类可读的彩色输出，用于本地开发
+)
+
+// 时间格式，除以下特殊值外按 time.Format 布局处理
+const (
+	DefaultTimeFormat = "2006-01-02 15:04:05.000Z07:00"
+	TimeFormatUnix    = "unix"   // 秒级时间戳
+	TimeFormatUnixMs  = "unixms" // 毫秒级时间戳
+)
+
+// WithFormat 设置输出格式：json（默认）、logfmt、console，未单独设置格式的 Sink 均使用该格式
+func WithFormat(format string) Option {
+	return func(c *config) {
+		c.format = format
+	}
+}
+
+// WithTimeFormat 设置时间格式，仅作用于当前 Logger
+func WithTimeFormat(layout string) Option {
+	return func(c *config) {
+		if layout != "" {
+			c.timeFormat = layout
+		}
+	}
+}
+
+// WithNoColor 关闭 console 格式的颜色，如输出到文件时
+func WithNoColor() Option {
+	return func(c *config) {
+		c.noColor = true
+	}
+}
+
+// timestampHook 按 Logger 自身的时间格式写入时间字段，避免修改 zerolog.TimeFieldFormat
+type timestampHook string
+
+func (h timestampHook) Run(e *Event, _ Level, _ string) {
+	now := time.Now()
+	switch string(h) {
+	case TimeFormatUnix:
+		e.Int64(zerolog.TimestampFieldName, now.Unix())
+	case TimeFormatUnixMs:
+		e.Int64(zerolog.TimestampFieldName, now.UnixMilli())
+	default:
+		e.Str(zerolog.TimestampFieldName, now.Format(string(h)))
+	}
+}
+
+// formatWriter 将 JSON 行转换为其他格式，Sync/Close 透传到底层输出
+type formatWriter struct {
+	io.Writer
+	out io.Writer
+}
+
+func (w formatWriter) Sync() error {
+	return syncWriter(w.out)
+}
+
+func (w formatWriter) Close() error {
+	return closeWriter(w.out)
+}
+
+// newFormatWriter 按格式包装输出，json 格式直接返回
+func newFormatWriter(out io.Writer, format string, noColor bool) (io.Writer, error) {
+	switch format {
+	case "", FormatJSON:
+		return out, nil
+	case FormatLogfmt:
+		return formatWriter{Writer: logfmtWriter{out: out}, out: out}, nil
+	case FormatConsole:
+		cw := zerolog.ConsoleWriter{
+			Out:     out,
+			NoColor: noColor,
+			// 时间已按 Logger 的格式写入，原样输出
+			FormatTimestamp: func(i any) string {
+				if noColor {
+					return fmt.Sprint(i)
+				}
+				return fmt.Sprintf("\x1b[90m%v\x1b[0m", i)
+			},
+		}
+		return formatWriter{Writer: cw, out: out}, nil
+	default:
+		return nil, fmt.Errorf("logging: invalid format %q", format)
+	}
+}
+
+// logfmtWriter 将 JSON 行转换为 logfmt：time、level、msg 在前，其余字段保持原有顺序
+type logfmtWriter struct {
+	out io.Writer
+}
+
+func (w logfmtWriter) Write(p []byte) (int, error) {
+	head := make([]string, 3) // time、level、msg
+	var tail []string
+	err := decodeLine(p, func(key string, raw json.RawMessage) {
+		pair := key + "=" + logfmtValue(raw)
+		switch key {
+		case zerolog.TimestampFieldName:
+			head[0] = pair
+		case zerolog.LevelFieldName:
+			head[1] = pair
+		case zerolog.MessageFieldName:
+			head[2] = pair
+		default:
+			tail = append(tail, pair)
+		}
+	})
+	if err != nil {
+		return w.out.Write(p) // 非 JSON 原样输出
+	}
+
+	var pairs []string
+	for _, pair := range head {
+		if pair != "" {
+			pairs = append(pairs, pair)
+		}
+	}
+	line := strings.Join(append(pairs, tail...), " ") + "\n"
+	if _, err := io.WriteString(w.out, line); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// decodeLine 按原有顺序遍历 JSON 行的顶层字段
+func decodeLine(p []byte, fn func(key string, raw json.RawMessage)) error {
+	dec := json.NewDecoder(bytes.NewReader(p))
+	dec.UseNumber()
+	if tok, err := dec.Token(); err != nil || tok != json.Delim('{') {
+		return errors.New("logging: not a json object")
+	}
+
+	for dec.More() {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		key, _ := tok.(string)
+		var raw json.RawMessage
+		if err := dec.Decode(&raw); err != nil {
+			return err
+		}
+		fn(key, raw)
+	}
+	return nil
+}
+
+// logfmtValue 字符串按需加引号，对象、数组保留紧凑 JSON
+func logfmtValue(raw json.RawMessage) string {
+	if len(raw) == 0 || raw[0] != '"' {
+		var buf bytes.Buffer
+		if err := json.Compact(&buf, raw); err != nil {
+			return string(raw)
+		}
+		if raw[0] == '{' || raw[0] == '[' {
+			return strconv.Quote(buf.String())
+		}
+		return buf.String()
+	}
+
+	var s string
+	if err := json.Unmarshal(raw, &s); err != nil {
+		return string(raw)
+	}
+	if s == "" || strings.ContainsAny(s, " =\"\t\r\n") {
+		return strconv.Quote(s)
+	}
+	return s
+}
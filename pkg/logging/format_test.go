@@ -0,0 +1,59 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormat(t *testing.T) {
+	globalFormat := zerolog.TimeFieldFormat
+	ctx := context.Background()
+
+	t.Run("logfmt", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := NewLogger(WithOutput(&buf), WithFormat(FormatLogfmt), WithTimeFormat(time.DateOnly))
+		l.Info(ctx).Str("user", "tom").Str("path", "/a b").Interface("tags", []string{"x"}).Msg("hello world")
+
+		line := strings.TrimSpace(buf.String())
+		assert.Regexp(t, `^time=\d{4}-\d{2}-\d{2} level=info message="hello world" `, line)
+		assert.Contains(t, line, `user=tom path="/a b" tags="[\"x\"]"`)
+	})
+
+	t.Run("console", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := NewLogger(WithOutput(&buf), WithFormat(FormatConsole), WithNoColor(), WithTimeFormat(time.TimeOnly))
+		l.Warn(ctx).Int("n", 1).Msg("slow")
+
+		assert.Regexp(t, `^\d{2}:\d{2}:\d{2} WRN slow n=1\n$`, buf.String())
+	})
+
+	t.Run("unix time", func(t *testing.T) {
+		var buf bytes.Buffer
+		l := NewLogger(WithOutput(&buf), WithTimeFormat(TimeFormatUnixMs))
+		l.Info(ctx).Msg("ts")
+
+		var m map[string]any
+		require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+		assert.InDelta(t, float64(time.Now().UnixMilli()), m["time"], 5000)
+	})
+
+	t.Run("sink format", func(t *testing.T) {
+		var jsonBuf, fmtBuf bytes.Buffer
+		l := NewLogger(WithSinks(Sink{Writer: &jsonBuf}, Sink{Writer: &fmtBuf, Format: FormatLogfmt}))
+		l.Info(ctx).Msg("both")
+
+		assert.True(t, json.Valid(jsonBuf.Bytes()))
+		assert.Contains(t, fmtBuf.String(), "message=both")
+	})
+
+	assert.Panics(t, func() { NewLogger(WithFormat("xml")) })
+	assert.Equal(t, globalFormat, zerolog.TimeFieldFormat, "不应修改 zerolog 全局时间格式")
+}
@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+)
+
+type levelHandlerConfig struct {
+	token     string
+	authorize func(r *http.Request) bool
+}
+
+type LevelHandlerOption func(*levelHandlerConfig)
+
+// WithLevelToken 要求请求携带 token（Header: Authorization: Bearer <token> 或 ?token=<token>）
+func WithLevelToken(token string) LevelHandlerOption {
+	return func(cfg *levelHandlerConfig) {
+		cfg.token = token
+	}
+}
+
+// WithLevelAuthorizer 自定义鉴权（如 IP 白名单），返回 false 时拒绝请求
+func WithLevelAuthorizer(fn func(r *http.Request) bool) LevelHandlerOption {
+	return func(cfg *levelHandlerConfig) {
+		cfg.authorize = fn
+	}
+}
+
+// levelRequest 级别调整请求，component 为空时调整全局级别，ttl 如 "10m"，为空表示永久生效
+type levelRequest struct {
+	Level     string `json:"level"`
+	Component string `json:"component"`
+	TTL       string `json:"ttl"`
+}
+
+// LevelHandler 日志级别管理接口，始终作用于当前的 std（Init 后自动切换）
+//
+//	GET              返回 {"level":"info","components":{"gorm":"warn"}}
+//	PUT/POST         {"level":"debug","component":"gorm","ttl":"10m"}
+//	DELETE ?component=gorm  移除组件级别覆盖
+//
+// 该接口可修改线上日志级别，应通过 WithLevelToken/WithLevelAuthorizer 鉴权，或仅挂载在内网端口
+func LevelHandler(opts ...LevelHandlerOption) http.Handler {
+	var cfg levelHandlerConfig
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if cfg.token != "" && !validToken(r, cfg.token) {
+			http.Error(w, http.StatusText(http.StatusUnauthorized), http.StatusUnauthorized)
+			return
+		}
+		if cfg.authorize != nil && !cfg.authorize(r) {
+			http.Error(w, http.StatusText(http.StatusForbidden), http.StatusForbidden)
+			return
+		}
+
+		levels := L().Levels()
+
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req levelRequest
+			if err := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<10)).Decode(&req); err != nil {
+				http.Error(w, "invalid request body", http.StatusBadRequest)
+				return
+			}
+			lvl, err := ParseLevel(req.Level)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			var ttl time.Duration
+			if req.TTL != "" {
+				if ttl, err = time.ParseDuration(req.TTL); err != nil || ttl < 0 {
+					http.Error(w, "invalid ttl", http.StatusBadRequest)
+					return
+				}
+			}
+			levels.SetLevelWithTTL(req.Component, lvl, ttl)
+		case http.MethodDelete:
+			component := r.URL.Query().Get("component")
+			if component == "" {
+				http.Error(w, "component required", http.StatusBadRequest)
+				return
+			}
+			levels.ResetLevelFor(component)
+		default:
+			w.Header().Set("Allow", "GET, PUT, POST, DELETE")
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		_ = json.NewEncoder(w).Encode(levels.Snapshot())
+	})
+}
+
+// validToken 校验访问 token
+func validToken(r *http.Request, token string) bool {
+	got := r.URL.Query().Get("token")
+	if auth := r.Header.Get("Authorization"); strings.HasPrefix(auth, "Bearer ") {
+		got = strings.TrimPrefix(auth, "Bearer ")
+	}
+	return subtle.ConstantTimeCompare([]byte(got), []byte(token)) == 1
+}
@@ -2,30 +2,151 @@ package logging
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
 )
 
-var std = NewLogger()
+// std 全局 Logger，Init/ReplaceGlobal 整体替换指针，不修改正在使用的 Logger
+var std atomic.Pointer[Logger]
 
+func init() {
+	std.Store(NewLogger())
+}
+
+var (
+	namedMu sync.Mutex
+	named   = make(map[string]*Logger) // Named 返回的句柄
+)
+
+// Init 重建 std，已通过 Named 获取的 Logger 同步切换到新的输出与级别，应在启动阶段调用
 func Init(opts ...Option) {
-	std = NewLogger(opts...)
+	setStd(NewLogger(opts...))
+}
+
+// ReplaceGlobal 替换 std（如测试中替换为内存 Logger），返回恢复为原 std 的函数
+func ReplaceGlobal(l *Logger) (restore func()) {
+	prev := setStd(l)
+	return func() { setStd(prev) }
+}
+
+// setStd 替换 std 并将各 Named 句柄指向新的子 Logger，返回原 std
+func setStd(l *Logger) *Logger {
+	if l.ref != nil {
+		cur := l.current()
+		l = &cur
+	}
+
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	prev := std.Swap(l)
+	for name, h := range named {
+		h.ref.Store(l.Named(name))
+	}
+	return prev
 }
 
 func L() *Logger {
-	return std
+	return std.Load()
 }
 
-func Debug(ctx context.Context, msg string) {
-	std.Debug(ctx).Caller(1).Msg(msg)
+// Named 返回 std 的子组件 Logger，相同名称返回同一实例，Init 后自动切换，可在包级变量中使用：
+//
+//	var log = logging.Named("order.service")
+func Named(name string) *Logger {
+	namedMu.Lock()
+	defer namedMu.Unlock()
+	if h, ok := named[name]; ok {
+		return h
+	}
+	h := &Logger{ref: new(atomic.Pointer[Logger])}
+	h.ref.Store(std.Load().Named(name))
+	named[name] = h
+	return h
 }
-func Info(ctx context.Context, msg string) {
-	std.Info(ctx).Caller(1).Msg(msg)
+
+// Sync 刷出 std 缓冲中的日志
+func Sync() error {
+	return L().Sync()
 }
-func Warn(ctx context.Context, msg string) {
-	std.Warn(ctx).Caller(1).Msg(msg)
+
+// Close 刷出并关闭 std 的输出，进程退出前调用（如 defer logging.Close()）
+func Close() error {
+	return L().Close()
 }
-func Error(ctx context.Context, msg string) {
-	std.Error(ctx).Caller(1).Msg(msg)
+
+// Dropped std 的异步输出因队列满丢弃的日志行数，可用于监控告警
+func Dropped() uint64 {
+	return L().Dropped()
 }
-func Errorw(ctx context.Context, err error) {
-	std.Error(ctx).Caller(1).Err(err).Msg("error")
+
+// osExit 便于测试替换
+var osExit = os.Exit
+
+// output 输出到 std，caller 指向包级函数的调用方
+func output(ctx context.Context, level Level, msg string, fields []Field) {
+	Apply(L().newEvent(ctx, level), fields...).Caller(2).Msg(msg)
+}
+
+func outputf(ctx context.Context, level Level, format string, args []any) {
+	L().newEvent(ctx, level).Caller(2).Msgf(format, args...)
+}
+
+func Debug(ctx context.Context, msg string, fields ...Field) {
+	output(ctx, DebugLevel, msg, fields)
+}
+func Info(ctx context.Context, msg string, fields ...Field) {
+	output(ctx, InfoLevel, msg, fields)
+}
+func Warn(ctx context.Context, msg string, fields ...Field) {
+	output(ctx, WarnLevel, msg, fields)
+}
+func Error(ctx context.Context, msg string, fields ...Field) {
+	output(ctx, ErrorLevel, msg, fields)
+}
+func Errorw(ctx context.Context, err error, fields ...Field) {
+	output(ctx, ErrorLevel, "error", append([]Field{Err(err)}, fields...))
+}
+
+// Fatal 输出日志，关闭 std 刷出缓冲后以状态码 1 退出
+func Fatal(ctx context.Context, msg string, fields ...Field) {
+	output(ctx, FatalLevel, msg, fields)
+	_ = Close()
+	osExit(1)
+}
+
+// Panic 输出日志，刷出缓冲后 panic(msg)
+func Panic(ctx context.Context, msg string, fields ...Field) {
+	output(ctx, PanicLevel, msg, fields)
+	_ = Sync()
+	panic(msg)
+}
+
+func Debugf(ctx context.Context, format string, args ...any) {
+	outputf(ctx, DebugLevel, format, args)
+}
+func Infof(ctx context.Context, format string, args ...any) {
+	outputf(ctx, InfoLevel, format, args)
+}
+func Warnf(ctx context.Context, format string, args ...any) {
+	outputf(ctx, WarnLevel, format, args)
+}
+func Errorf(ctx context.Context, format string, args ...any) {
+	outputf(ctx, ErrorLevel, format, args)
+}
+
+// Fatalf 同 Fatal，按 format 格式化消息
+func Fatalf(ctx context.Context, format string, args ...any) {
+	outputf(ctx, FatalLevel, format, args)
+	_ = Close()
+	osExit(1)
+}
+
+// Panicf 同 Panic，按 format 格式化消息
+func Panicf(ctx context.Context, format string, args ...any) {
+	msg := fmt.Sprintf(format, args...)
+	output(ctx, PanicLevel, msg, nil)
+	_ = Sync()
+	panic(msg)
 }
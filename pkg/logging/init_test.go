@@ -1,12 +1,24 @@
 package logging
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 func TestCtxInfo(t *testing.T) {
-	Init()
+	var buf bytes.Buffer
+	defer ReplaceGlobal(NewLogger(WithOutput(&buf)))()
 
 	t.Run("CtxLog", func(t *testing.T) {
 		ctx := context.Background()
@@ -23,5 +35,60 @@ func TestCtxInfo(t *testing.T) {
 		Info(ctx, "test444")
 
 		L().Info(ctx).Str("field", "2222").Msg("test555")
+
+		lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+		require.Len(t, lines, 5)
+		assert.NotContains(t, lines[0], "requestId")
+		assert.Contains(t, lines[1], `"requestId":"ABC123"`)
+		assert.Contains(t, lines[2], `"field":"0000"`)
+		assert.Equal(t, 1, strings.Count(lines[3], `"field"`))
+		assert.Contains(t, lines[3], `"field":"1111"`)
+		assert.Equal(t, 1, strings.Count(lines[4], `"field"`), "事件字段覆盖 ctx 中的同名 field")
+		assert.Contains(t, lines[4], `"field":"2222"`)
 	})
 }
+
+func TestPackageHelpers(t *testing.T) {
+	var buf bytes.Buffer
+	Init(WithOutput(&buf), WithLevel(DebugLevel))
+	defer Init()
+	ctx := WithFields(context.Background(), Str("requestId", "ABC123"))
+
+	lines := func() []map[string]any {
+		var out []map[string]any
+		for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+			var m map[string]any
+			require.NoError(t, json.Unmarshal([]byte(line), &m))
+			out = append(out, m)
+		}
+		buf.Reset()
+		return out
+	}
+
+	Infof(ctx, "user %d login", 42)
+	Warn(ctx, "slow", Int("cost_ms", 300))
+	Errorw(ctx, errors.New("boom"), Str("op", "pay"))
+	_, file, line, _ := runtime.Caller(0)
+	caller := filepath.Base(filepath.Dir(file)) + "/" + filepath.Base(file)
+
+	got := lines()
+	require.Len(t, got, 3)
+	assert.Equal(t, "user 42 login", got[0]["message"])
+	assert.Equal(t, fmt.Sprintf("%s:%d", caller, line-3), got[0]["caller"])
+	assert.EqualValues(t, 300, got[1]["cost_ms"])
+	assert.Equal(t, fmt.Sprintf("%s:%d", caller, line-2), got[1]["caller"])
+	assert.Equal(t, "boom", got[2]["error"])
+	assert.Equal(t, "pay", got[2]["op"])
+	assert.Equal(t, "ABC123", got[2]["requestId"])
+
+	var code int
+	osExit = func(c int) { code = c }
+	defer func() { osExit = os.Exit }()
+	Fatalf(ctx, "config %s missing", "db")
+	assert.Equal(t, 1, code)
+	assert.Contains(t, buf.String(), `"level":"fatal"`)
+
+	buf.Reset()
+	assert.PanicsWithValue(t, "bad state", func() { Panic(ctx, "bad state") })
+	assert.Equal(t, "panic", lines()[0]["level"])
+}
@@ -0,0 +1,225 @@
+package logging
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+// LevelController 运行时可调整的日志级别，支持按组件名（如 gorm、order.service）覆盖
+type LevelController struct {
+	level atomic.Int32
+
+	mu        sync.RWMutex
+	overrides map[string]Level // 组件名 -> 级别
+	gens      map[string]uint64
+}
+
+// NewLevelController 创建级别控制器
+func NewLevelController(level Level) *LevelController {
+	c := &LevelController{
+		overrides: make(map[string]Level),
+		gens:      make(map[string]uint64),
+	}
+	c.level.Store(int32(level))
+	return c
+}
+
+// Level 全局级别
+func (c *LevelController) Level() Level {
+	return Level(c.level.Load())
+}
+
+// SetLevel 设置全局级别
+func (c *LevelController) SetLevel(level Level) {
+	c.mu.Lock()
+	c.setLocked("", level)
+	c.mu.Unlock()
+}
+
+// LevelFor 获取组件级别：按 "." 分段取最长前缀匹配，未覆盖时返回全局级别
+func (c *LevelController) LevelFor(name string) Level {
+	if name == "" {
+		return c.Level()
+	}
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if len(c.overrides) == 0 {
+		return c.Level()
+	}
+	for n := name; n != ""; {
+		if lvl, ok := c.overrides[n]; ok {
+			return lvl
+		}
+		i := strings.LastIndexByte(n, '.')
+		if i < 0 {
+			break
+		}
+		n = n[:i]
+	}
+	return c.Level()
+}
+
+// SetLevelFor 覆盖组件级别，name 为空时设置全局级别
+func (c *LevelController) SetLevelFor(name string, level Level) {
+	c.mu.Lock()
+	c.setLocked(name, level)
+	c.mu.Unlock()
+}
+
+// ResetLevelFor 移除组件级别覆盖
+func (c *LevelController) ResetLevelFor(name string) {
+	c.mu.Lock()
+	c.resetLocked(name)
+	c.mu.Unlock()
+}
+
+// setLocked 设置级别并递增版本号，需持有写锁
+func (c *LevelController) setLocked(name string, level Level) {
+	c.gens[name]++
+	if name == "" {
+		c.level.Store(int32(level))
+		return
+	}
+	c.overrides[name] = level
+}
+
+// resetLocked 移除组件级别覆盖并递增版本号，需持有写锁
+func (c *LevelController) resetLocked(name string) {
+	delete(c.overrides, name)
+	c.gens[name]++
+}
+
+// SetLevelWithTTL 临时调整级别，ttl 后自动恢复为调整前的值（期间再次调整则不恢复）
+func (c *LevelController) SetLevelWithTTL(name string, level Level, ttl time.Duration) {
+	// 读取原值、设置与记录版本号在同一把锁内完成，避免与并发调整交错
+	c.mu.Lock()
+	prev, overridden := c.overrides[name]
+	if name == "" {
+		prev, overridden = c.Level(), true
+	}
+	c.setLocked(name, level)
+	gen := c.gens[name]
+	c.mu.Unlock()
+
+	if ttl <= 0 {
+		return
+	}
+	time.AfterFunc(ttl, func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		if c.gens[name] != gen {
+			return
+		}
+		if overridden {
+			c.setLocked(name, prev)
+		} else {
+			c.resetLocked(name)
+		}
+	})
+}
+
+// Overrides 返回当前的组件级别覆盖
+func (c *LevelController) Overrides() map[string]Level {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make(map[string]Level, len(c.overrides))
+	for k, v := range c.overrides {
+		out[k] = v
+	}
+	return out
+}
+
+// Enabled 判断组件在指定级别下是否输出
+func (c *LevelController) Enabled(name string, level Level) bool {
+	return level != Disabled && level >= c.LevelFor(name) && level >= zerolog.GlobalLevel()
+}
+
+// LevelConfig 级别配置，可嵌入应用配置结构体并在配置热更新时调用 ApplyLevels
+type LevelConfig struct {
+	Level      string            `mapstructure:"level" json:"level" yaml:"level"`
+	Components map[string]string `mapstructure:"components" json:"components" yaml:"components"` // 如 gorm: warn
+}
+
+// Apply 应用级别配置：设置全局级别，并以 Components 替换全部组件覆盖
+func (c *LevelController) Apply(cfg LevelConfig) error {
+	overrides := make(map[string]Level, len(cfg.Components))
+	for name, s := range cfg.Components {
+		lvl, err := ParseLevel(s)
+		if err != nil {
+			return fmt.Errorf("component %s: %w", name, err)
+		}
+		overrides[name] = lvl
+	}
+
+	var global Level
+	if cfg.Level != "" {
+		lvl, err := ParseLevel(cfg.Level)
+		if err != nil {
+			return err
+		}
+		global = lvl
+	}
+
+	c.mu.Lock()
+	for name := range c.overrides {
+		c.gens[name]++
+	}
+	for name := range overrides {
+		c.gens[name]++
+	}
+	c.overrides = overrides
+	if cfg.Level != "" {
+		c.setLocked("", global)
+	}
+	c.mu.Unlock()
+	return nil
+}
+
+// Snapshot 返回当前级别配置
+func (c *LevelController) Snapshot() LevelConfig {
+	overrides := c.Overrides()
+	cfg := LevelConfig{Level: c.Level().String(), Components: make(map[string]string, len(overrides))}
+	names := make([]string, 0, len(overrides))
+	for name := range overrides {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		cfg.Components[name] = overrides[name].String()
+	}
+	return cfg
+}
+
+// ParseLevel 解析级别字符串，如 debug、info、warn、error、disabled
+func ParseLevel(s string) (Level, error) {
+	lvl, err := zerolog.ParseLevel(strings.ToLower(strings.TrimSpace(s)))
+	if err != nil {
+		return zerolog.NoLevel, fmt.Errorf("invalid log level %q", s)
+	}
+	if lvl == zerolog.NoLevel {
+		return lvl, fmt.Errorf("invalid log level %q", s)
+	}
+	return lvl, nil
+}
+
+// Levels 返回 std 的级别控制器
+func Levels() *LevelController {
+	return L().levels
+}
+
+// SetLevel 设置 std 的全局级别
+func SetLevel(level Level) {
+	L().levels.SetLevel(level)
+}
+
+// ApplyLevels 将级别配置应用到 std，用于配置热更新
+func ApplyLevels(cfg LevelConfig) error {
+	return L().levels.Apply(cfg)
+}
@@ -0,0 +1,107 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLevelController(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf), WithLevel(InfoLevel))
+	gormLog := l.Named("gorm")
+	orderLog := l.Named("order").Named("service")
+	ctx := context.Background()
+
+	l.Debug(ctx).Msg("root debug")
+	gormLog.Debug(ctx).Msg("gorm debug")
+	assert.Empty(t, buf.String())
+
+	l.Levels().SetLevelFor("gorm", DebugLevel)
+	l.Levels().SetLevelFor("order", ErrorLevel)
+	gormLog.Debug(ctx).Msg("gorm debug")
+	orderLog.Warn(ctx).Msg("order warn")
+	l.Debug(ctx).Msg("root debug")
+	assert.Contains(t, buf.String(), `"logger":"gorm"`)
+	assert.NotContains(t, buf.String(), "order warn")
+	assert.NotContains(t, buf.String(), "root debug")
+	assert.Equal(t, ErrorLevel, l.Levels().LevelFor("order.service.repo"))
+
+	l.Levels().ResetLevelFor("order")
+	assert.True(t, orderLog.Enabled(WarnLevel))
+
+	require.NoError(t, l.Levels().Apply(LevelConfig{Level: "warn", Components: map[string]string{"redis": "debug"}}))
+	assert.Equal(t, WarnLevel, l.Levels().Level())
+	assert.Equal(t, WarnLevel, l.Levels().LevelFor("gorm.x"), "Apply 应替换全部组件覆盖")
+	assert.Error(t, l.Levels().Apply(LevelConfig{Level: "verbose"}))
+}
+
+func TestLevelTTL(t *testing.T) {
+	c := NewLevelController(InfoLevel)
+
+	c.SetLevelWithTTL("", DebugLevel, 20*time.Millisecond)
+	c.SetLevelWithTTL("gorm", DebugLevel, 20*time.Millisecond)
+	assert.Equal(t, DebugLevel, c.Level())
+	assert.Equal(t, DebugLevel, c.LevelFor("gorm"))
+
+	assert.Eventually(t, func() bool {
+		return c.Level() == InfoLevel && len(c.Overrides()) == 0
+	}, time.Second, 5*time.Millisecond)
+
+	// 期间再次调整则不自动恢复
+	c.SetLevelWithTTL("redis", DebugLevel, 20*time.Millisecond)
+	c.SetLevelFor("redis", WarnLevel)
+	time.Sleep(50 * time.Millisecond)
+	assert.Equal(t, WarnLevel, c.LevelFor("redis"))
+}
+
+func TestLevelHandler(t *testing.T) {
+	defer ReplaceGlobal(NewLogger(WithOutput(&bytes.Buffer{})))()
+	h := LevelHandler()
+
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"debug","component":"gorm"}`)))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"info","components":{"gorm":"debug"}}`, w.Body.String())
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodPut, "/log/level", strings.NewReader(`{"level":"loud"}`)))
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+
+	w = httptest.NewRecorder()
+	h.ServeHTTP(w, httptest.NewRequest(http.MethodDelete, "/log/level?component=gorm", nil))
+	require.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"level":"info","components":{}}`, w.Body.String())
+}
+
+func TestLevelHandlerAuth(t *testing.T) {
+	defer ReplaceGlobal(NewLogger(WithOutput(&bytes.Buffer{})))()
+	h := LevelHandler(WithLevelToken("secret"), WithLevelAuthorizer(func(r *http.Request) bool {
+		return r.Header.Get("X-Internal") == "1"
+	}))
+
+	put := func(header map[string]string, target string) int {
+		r := httptest.NewRequest(http.MethodPut, target, strings.NewReader(`{"level":"debug"}`))
+		for k, v := range header {
+			r.Header.Set(k, v)
+		}
+		w := httptest.NewRecorder()
+		h.ServeHTTP(w, r)
+		return w.Code
+	}
+
+	assert.Equal(t, http.StatusUnauthorized, put(nil, "/log/level"))
+	assert.Equal(t, http.StatusUnauthorized, put(map[string]string{"Authorization": "Bearer wrong"}, "/log/level"))
+	assert.Equal(t, http.StatusForbidden, put(map[string]string{"Authorization": "Bearer secret"}, "/log/level"))
+	assert.Equal(t, InfoLevel, L().Levels().Level(), "未通过鉴权不应修改级别")
+
+	assert.Equal(t, http.StatusOK, put(map[string]string{"X-Internal": "1"}, "/log/level?token=secret"))
+	assert.Equal(t, DebugLevel, L().Levels().Level())
+}
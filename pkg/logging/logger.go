@@ -2,11 +2,23 @@ package logging
 
 import (
 	"context"
+	"io"
+	"os"
+	"sync/atomic"
 )
 
+// LoggerFieldName 组件名字段
+const LoggerFieldName = "logger"
+
 type Logger struct {
+	ref        *atomic.Pointer[Logger] // 非空时为 Named 返回的全局句柄，始终作用于其指向的当前 Logger
 	base       logger
-	callerSkip int // 额外 skip 层数
+	callerSkip int              // 额外 skip 层数
+	name       string           // 组件名，用于按组件调整级别
+	levels     *LevelController // 运行时级别，派生 Logger 间共享
+	output     io.Writer
+	async      []*AsyncWriter // 输出中的异步写入器，用于统计丢弃行数
+	sampler    *sampler
 }
 
 func NewLogger(opts ...Option) *Logger {
@@ -15,15 +27,42 @@ func NewLogger(opts ...Option) *Logger {
 		opt(cfg)
 	}
 
-	return &Logger{
-		base: newZerolog(cfg),
+	out, err := buildOutput(cfg)
+	if err != nil {
+		panic(err) // 配置非法，启动阶段暴露
+	}
+	levels := NewLevelController(cfg.level)
+	if cfg.levelConfig != nil {
+		if err := levels.Apply(*cfg.levelConfig); err != nil {
+			panic(err)
+		}
+	}
+	l := &Logger{
+		base:   newZerolog(cfg, out),
+		levels: levels,
+		output: out,
+		async:  asyncWriters(cfg.sinks),
+	}
+	if cfg.sample != nil {
+		l.sampler = newSampler(*cfg.sample, l.base)
+		l.base = l.base.Hook(l.sampler)
 	}
+	return l
 }
 
-func (l Logger) clone() Logger {
+// current 解析全局句柄，普通 Logger 返回自身
+func (l Logger) current() Logger {
+	if l.ref != nil {
+		return *l.ref.Load()
+	}
 	return l
 }
 
+// clone 复制当前 Logger，派生自全局句柄时不再跟随 Init 切换
+func (l Logger) clone() Logger {
+	return l.current()
+}
+
 // With 返回新的 Logger
 func (l Logger) With() Logger {
 	return l.clone()
@@ -39,6 +78,30 @@ func (l Logger) WithCaller(skip int) Logger {
 	return nl
 }
 
+// Named 返回子组件 Logger，输出 logger 字段并共享输出与级别控制器
+// 已命名时以 "." 拼接，如 L().Named("order").Named("service") 的名称为 order.service
+// 级别可按名称前缀单独调整，见 LevelController.SetLevelFor
+func (l Logger) Named(name string) *Logger {
+	nl := l.clone()
+	if nl.name != "" && name != "" {
+		name = nl.name + "." + name
+	}
+	nl.name = name
+	return &nl
+}
+
+// Name 组件名，std 为空
+func (l Logger) Name() string {
+	l = l.current()
+	return l.name
+}
+
+// Levels 返回级别控制器
+func (l Logger) Levels() *LevelController {
+	l = l.current()
+	return l.levels
+}
+
 // attachFields 应用 ctx 中的 Field 到 Event
 func (l Logger) attachFields(ctx context.Context, e *Event) *Event {
 	if e == nil {
@@ -47,26 +110,80 @@ func (l Logger) attachFields(ctx context.Context, e *Event) *Event {
 
 	// 从 ctx 获取 Field
 	if ctx != nil {
-		for _, f := range FieldsFrom(ctx) {
-			f(e)
-		}
+		attachCtxFields(ctx, e)
 	}
 
 	return e
 }
 
+// newEvent 按运行时级别过滤后创建 Event，未启用时返回 nil
+func (l Logger) newEvent(ctx context.Context, level Level) *Event {
+	l = l.current()
+	if !l.Enabled(level) {
+		return nil
+	}
+	e := l.base.WithLevel(level)
+	if l.name != "" {
+		e.Str(LoggerFieldName, l.name)
+	}
+	return l.attachFields(ctx, e)
+}
+
 func (l Logger) Debug(ctx context.Context) *Event {
-	return l.attachFields(ctx, l.base.Debug())
+	return l.newEvent(ctx, DebugLevel)
 }
 
 func (l Logger) Info(ctx context.Context) *Event {
-	return l.attachFields(ctx, l.base.Info())
+	return l.newEvent(ctx, InfoLevel)
 }
 
 func (l Logger) Warn(ctx context.Context) *Event {
-	return l.attachFields(ctx, l.base.Warn())
+	return l.newEvent(ctx, WarnLevel)
 }
 
 func (l Logger) Error(ctx context.Context) *Event {
-	return l.attachFields(ctx, l.base.Error())
+	return l.newEvent(ctx, ErrorLevel)
+}
+
+// Log 按指定级别创建 Event，level 为 Disabled 时返回 nil（后续链式调用为空操作）
+func (l Logger) Log(ctx context.Context, level Level) *Event {
+	return l.newEvent(ctx, level)
+}
+
+// Enabled 判断指定级别的日志是否会输出，用于跳过昂贵的字段构建
+func (l Logger) Enabled(level Level) bool {
+	l = l.current()
+	return l.levels.Enabled(l.name, level)
+}
+
+// Sync 刷出缓冲中的日志，输出不支持 Sync 时为空操作
+func (l Logger) Sync() error {
+	l = l.current()
+	if l.sampler != nil {
+		l.sampler.flush()
+	}
+	return syncWriter(l.output)
+}
+
+// Close 刷出并关闭输出，进程退出前调用；Stdout/Stderr 不会被关闭
+func (l Logger) Close() error {
+	l = l.current()
+	if l.sampler != nil {
+		l.sampler.flush()
+	}
+	return closeWriter(l.output)
+}
+
+// Dropped 输出中的 AsyncWriter 因队列满丢弃的日志行数之和
+func (l Logger) Dropped() uint64 {
+	l = l.current()
+	var n uint64
+	for _, w := range l.async {
+		n += w.Dropped()
+	}
+	return n
+}
+
+func isStdStream(w io.Writer) bool {
+	return w == os.Stdout || w == os.Stderr
 }
@@ -0,0 +1,216 @@
+// Package logtest 提供测试中捕获与断言日志的工具
+package logtest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/rs/zerolog"
+)
+
+// Entry 一条已捕获的日志
+type Entry struct {
+	Level   logging.Level
+	Message string
+	Fields  map[string]any // 不含 level、message，数字为 float64
+	Raw     string
+}
+
+// Field 获取字段值
+func (e Entry) Field(key string) (any, bool) {
+	v, ok := e.Fields[key]
+	return v, ok
+}
+
+// Entries 日志集合，Filter 方法均返回新的集合
+type Entries []Entry
+
+// FilterLevel 按级别过滤
+func (es Entries) FilterLevel(level logging.Level) Entries {
+	return es.filter(func(e Entry) bool { return e.Level == level })
+}
+
+// FilterMessage 按消息精确过滤
+func (es Entries) FilterMessage(msg string) Entries {
+	return es.filter(func(e Entry) bool { return e.Message == msg })
+}
+
+// FilterMessageContains 按消息包含过滤
+func (es Entries) FilterMessageContains(sub string) Entries {
+	return es.filter(func(e Entry) bool { return strings.Contains(e.Message, sub) })
+}
+
+// FilterField 按字段值过滤，val 按 JSON 归一化后比较（如 int 与 float64 视为相等）
+func (es Entries) FilterField(key string, val any) Entries {
+	want := normalize(val)
+	return es.filter(func(e Entry) bool {
+		got, ok := e.Fields[key]
+		return ok && reflect.DeepEqual(got, want)
+	})
+}
+
+// FilterFieldKey 按字段存在过滤
+func (es Entries) FilterFieldKey(key string) Entries {
+	return es.filter(func(e Entry) bool {
+		_, ok := e.Fields[key]
+		return ok
+	})
+}
+
+// Len 条数
+func (es Entries) Len() int {
+	return len(es)
+}
+
+func (es Entries) filter(fn func(Entry) bool) Entries {
+	var out Entries
+	for _, e := range es {
+		if fn(e) {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// Observer 内存日志观察者，并发安全
+type Observer struct {
+	mu      sync.Mutex
+	entries Entries
+}
+
+// New 创建输出到内存的 Logger，默认级别 Debug；opts 中的输出设置会被忽略
+func New(opts ...logging.Option) (*logging.Logger, *Observer) {
+	o := &Observer{}
+	all := append([]logging.Option{logging.WithLevel(logging.DebugLevel)}, opts...)
+	all = append(all, logging.WithSinks(logging.Sink{Writer: o, Format: logging.FormatJSON}))
+	return logging.NewLogger(all...), o
+}
+
+// Swap 将全局 Logger（logging.L() 及 logging.Named）替换为内存 Logger，测试结束后自动恢复
+// 修改全局状态，使用 Swap 的测试不应调用 t.Parallel
+func Swap(t testing.TB, opts ...logging.Option) *Observer {
+	t.Helper()
+	l, o := New(opts...)
+	restore := logging.ReplaceGlobal(l)
+	t.Cleanup(restore)
+	return o
+}
+
+func (o *Observer) Write(p []byte) (int, error) {
+	return o.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (o *Observer) WriteLevel(level logging.Level, p []byte) (int, error) {
+	e := Entry{Level: level, Raw: string(bytes.TrimSpace(p))}
+
+	dec := json.NewDecoder(bytes.NewReader(p))
+	if err := dec.Decode(&e.Fields); err != nil {
+		return 0, fmt.Errorf("logtest: decode log line: %w", err)
+	}
+	if msg, ok := e.Fields[zerolog.MessageFieldName].(string); ok {
+		e.Message = msg
+	}
+	if s, ok := e.Fields[zerolog.LevelFieldName].(string); ok {
+		if lvl, err := zerolog.ParseLevel(s); err == nil {
+			e.Level = lvl
+		}
+	}
+	delete(e.Fields, zerolog.MessageFieldName)
+	delete(e.Fields, zerolog.LevelFieldName)
+
+	o.mu.Lock()
+	o.entries = append(o.entries, e)
+	o.mu.Unlock()
+	return len(p), nil
+}
+
+// All 返回全部已捕获日志的副本
+func (o *Observer) All() Entries {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return append(Entries(nil), o.entries...)
+}
+
+// Len 已捕获条数
+func (o *Observer) Len() int {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return len(o.entries)
+}
+
+// Reset 清空已捕获日志
+func (o *Observer) Reset() {
+	o.mu.Lock()
+	o.entries = nil
+	o.mu.Unlock()
+}
+
+// AssertLogged 断言存在指定级别与消息的日志，kv 为需匹配的字段键值对，返回首条匹配的日志
+func (o *Observer) AssertLogged(t testing.TB, level logging.Level, msg string, kv ...any) Entry {
+	t.Helper()
+	found, err := o.find(level, msg, kv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) == 0 {
+		t.Errorf("logtest: no %s entry %q with fields %v, got:\n%s", level, msg, kv, o.dump())
+		return Entry{}
+	}
+	return found[0]
+}
+
+// AssertNotLogged 断言不存在指定级别与消息的日志
+func (o *Observer) AssertNotLogged(t testing.TB, level logging.Level, msg string, kv ...any) {
+	t.Helper()
+	found, err := o.find(level, msg, kv)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(found) > 0 {
+		t.Errorf("logtest: unexpected %s entry %q: %s", level, msg, found[0].Raw)
+	}
+}
+
+func (o *Observer) find(level logging.Level, msg string, kv []any) (Entries, error) {
+	if len(kv)%2 != 0 {
+		return nil, fmt.Errorf("logtest: odd number of key-value arguments")
+	}
+	found := o.All().FilterLevel(level).FilterMessage(msg)
+	for i := 0; i < len(kv); i += 2 {
+		key, ok := kv[i].(string)
+		if !ok {
+			return nil, fmt.Errorf("logtest: field key %v is not a string", kv[i])
+		}
+		found = found.FilterField(key, kv[i+1])
+	}
+	return found, nil
+}
+
+func (o *Observer) dump() string {
+	var b strings.Builder
+	for _, e := range o.All() {
+		b.WriteString("  ")
+		b.WriteString(e.Raw)
+		b.WriteByte('\n')
+	}
+	return b.String()
+}
+
+// normalize 按 JSON 编解码归一化期望值，使之与解析出的字段可比较
+func normalize(v any) any {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return v
+	}
+	var out any
+	if err := json.Unmarshal(b, &out); err != nil {
+		return v
+	}
+	return out
+}
@@ -0,0 +1,53 @@
+package logtest_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/lpphub/goweb/pkg/logging"
+	"github.com/lpphub/goweb/pkg/logging/logtest"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestObserver(t *testing.T) {
+	l, obs := logtest.New()
+	ctx := logging.WithFields(context.Background(), logging.Str("request_id", "r1"))
+
+	l.Debug(ctx).Msg("start")
+	l.Warn(ctx).Int("retry", 2).Bool("ok", false).Msg("slow")
+
+	e := obs.AssertLogged(t, logging.WarnLevel, "slow", "request_id", "r1", "retry", 2)
+	assert.Equal(t, false, e.Fields["ok"])
+	obs.AssertNotLogged(t, logging.ErrorLevel, "slow")
+
+	assert.Equal(t, 2, obs.Len())
+	assert.Equal(t, 1, obs.All().FilterLevel(logging.DebugLevel).Len())
+	assert.Equal(t, 2, obs.All().FilterField("request_id", "r1").Len())
+	assert.Equal(t, 1, obs.All().FilterMessageContains("sl").FilterFieldKey("retry").Len())
+
+	obs.Reset()
+	assert.Zero(t, obs.Len())
+}
+
+func TestSwap(t *testing.T) {
+	prev := logging.L()
+	named := logging.Named("logtest.svc")
+
+	t.Run("Swapped", func(t *testing.T) {
+		obs := logtest.Swap(t, logging.WithLevel(logging.InfoLevel))
+		ctx := context.Background()
+
+		logging.Errorw(ctx, errors.New("boom"), logging.Str("op", "pay"))
+		logging.Debug(ctx, "hidden")
+		named.Info(ctx).Msg("from named")
+
+		e := obs.AssertLogged(t, logging.ErrorLevel, "error", "error", "boom", "op", "pay")
+		assert.Contains(t, e.Fields["caller"], "logtest/logtest_test.go:")
+		obs.AssertNotLogged(t, logging.DebugLevel, "hidden")
+		obs.AssertLogged(t, logging.InfoLevel, "from named", "logger", "logtest.svc")
+	})
+
+	assert.Same(t, prev, logging.L(), "测试结束后应恢复全局 Logger")
+	assert.Equal(t, prev.Levels(), named.Levels())
+}
@@ -0,0 +1,338 @@
+package logging
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"regexp"
+	"sort"
+	"strings"
+
+	"github.com/rs/zerolog"
+)
+
+// 遮盖策略
+const (
+	MaskFull    = "full"    // 整体替换为 ******
+	MaskPartial = "partial" // 保留首尾部分字符，如 138****678
+	MaskHash    = "hash"    // 替换为 sha256 摘要前缀，便于关联同一值
+)
+
+// MaskedValue 整体遮盖后的值
+const MaskedValue = "******"
+
+// DefaultMaskKeys 默认始终遮盖的字段（不区分大小写）
+var DefaultMaskKeys = []string{"password", "passwd", "secret", "token", "access_token", "refresh_token", "authorization", "cookie", "api_key"}
+
+// Detector 敏感数据识别规则，Pattern 含子匹配时仅遮盖第 1 个子匹配
+type Detector struct {
+	Name     string
+	Pattern  *regexp.Regexp
+	Validate func(match string) bool // 可选，进一步校验匹配内容，如银行卡 Luhn 校验
+}
+
+// 内置识别规则
+var (
+	DetectEmail  = Detector{Name: "email", Pattern: regexp.MustCompile(`[A-Za-z0-9._%+-]+@[A-Za-z0-9.-]+\.[A-Za-z]{2,}`)}
+	DetectPhone  = Detector{Name: "phone", Pattern: regexp.MustCompile(`(?:\+86[- ]?)?\b1[3-9]\d{9}\b|\+[1-9]\d{7,14}\b`)}
+	DetectCard   = Detector{Name: "card", Pattern: regexp.MustCompile(`\b(?:\d[ -]?){12,18}\d\b`), Validate: luhnValid}
+	DetectJWT    = Detector{Name: "jwt", Pattern: regexp.MustCompile(`eyJ[A-Za-z0-9_-]+\.eyJ[A-Za-z0-9_-]+\.[A-Za-z0-9_-]+`)}
+	DetectBearer = Detector{Name: "bearer", Pattern: regexp.MustCompile(`(?i)\bbearer\s+([A-Za-z0-9\-._~+/]+=*)`)}
+)
+
+// DefaultDetectors 默认启用的识别规则
+var DefaultDetectors = []Detector{DetectJWT, DetectBearer, DetectEmail, DetectCard, DetectPhone}
+
+// skipDetectKeys 内置字段不做内容识别，避免时间等误判为卡号
+var skipDetectKeys = map[string]struct{}{
+	zerolog.TimestampFieldName: {},
+	zerolog.LevelFieldName:     {},
+	zerolog.CallerFieldName:    {},
+	LoggerFieldName:            {},
+}
+
+type maskConfig struct {
+	keys      map[string]struct{}
+	detectors []Detector
+	strategy  string
+}
+
+type MaskOption func(*maskConfig)
+
+// WithMaskKeys 设置始终遮盖的字段，默认 DefaultMaskKeys
+func WithMaskKeys(keys ...string) MaskOption {
+	return func(c *maskConfig) {
+		c.keys = make(map[string]struct{}, len(keys))
+		for _, k := range keys {
+			c.keys[strings.ToLower(k)] = struct{}{}
+		}
+	}
+}
+
+// WithMaskDetectors 设置识别规则，默认 DefaultDetectors，不传参数时关闭内容识别
+func WithMaskDetectors(detectors ...Detector) MaskOption {
+	return func(c *maskConfig) {
+		c.detectors = detectors
+	}
+}
+
+// WithMaskStrategy 设置识别出的敏感内容的遮盖策略：full、partial（默认）、hash，WithMaskKeys 中的字段始终整体遮盖
+func WithMaskStrategy(strategy string) MaskOption {
+	return func(c *maskConfig) {
+		c.strategy = strategy
+	}
+}
+
+// WithMasking 启用敏感数据遮盖：输出前对 ctx fields、事件字段及 message 统一处理（含嵌套对象、数组与数字），未命中的日志行原样输出
+func WithMasking(opts ...MaskOption) Option {
+	return func(c *config) {
+		mc := &maskConfig{detectors: DefaultDetectors, strategy: MaskPartial}
+		WithMaskKeys(DefaultMaskKeys...)(mc)
+		for _, opt := range opts {
+			opt(mc)
+		}
+		c.mask = mc
+	}
+}
+
+// newMasker 校验配置并创建遮盖器
+func newMasker(cfg *maskConfig) (*masker, error) {
+	switch cfg.strategy {
+	case MaskFull, MaskPartial, MaskHash:
+	default:
+		return nil, fmt.Errorf("logging: invalid mask strategy %q", cfg.strategy)
+	}
+	return &masker{cfg: cfg}, nil
+}
+
+type masker struct {
+	cfg *maskConfig
+}
+
+// mask 遮盖单个值
+func (m *masker) mask(s string) string {
+	switch m.cfg.strategy {
+	case MaskFull:
+		return MaskedValue
+	case MaskHash:
+		sum := sha256.Sum256([]byte(s))
+		return "sha256:" + hex.EncodeToString(sum[:8])
+	}
+
+	if i := strings.LastIndexByte(s, '@'); i > 0 { // 邮箱保留域名
+		return m.partial(s[:i]) + s[i:]
+	}
+	return m.partial(s)
+}
+
+func (m *masker) partial(s string) string {
+	r := []rune(s)
+	if len(r) <= 4 {
+		return MaskedValue
+	}
+	head, tail := min(3, len(r)/3), min(4, len(r)/3)
+	return string(r[:head]) + "****" + string(r[len(r)-tail:])
+}
+
+// detect 遮盖字符串中识别出的敏感内容，各规则在原文上匹配，重叠时靠前的规则优先
+func (m *masker) detect(s string) string {
+	var spans [][2]int
+	for _, d := range m.cfg.detectors {
+		for _, loc := range d.Pattern.FindAllStringSubmatchIndex(s, -1) {
+			if d.Validate != nil && !d.Validate(s[loc[0]:loc[1]]) {
+				continue
+			}
+			span := [2]int{loc[0], loc[1]}
+			if len(loc) >= 4 && loc[2] >= 0 {
+				span = [2]int{loc[2], loc[3]}
+			}
+			if !overlaps(spans, span) {
+				spans = append(spans, span)
+			}
+		}
+	}
+	if len(spans) == 0 {
+		return s
+	}
+
+	sort.Slice(spans, func(i, j int) bool { return spans[i][0] < spans[j][0] })
+	var b strings.Builder
+	last := 0
+	for _, sp := range spans {
+		b.WriteString(s[last:sp[0]])
+		b.WriteString(m.mask(s[sp[0]:sp[1]]))
+		last = sp[1]
+	}
+	b.WriteString(s[last:])
+	return b.String()
+}
+
+func overlaps(spans [][2]int, span [2]int) bool {
+	for _, sp := range spans {
+		if span[0] < sp[1] && sp[0] < span[1] {
+			return true
+		}
+	}
+	return false
+}
+
+func (m *masker) sensitiveKey(key string) bool {
+	_, ok := m.cfg.keys[strings.ToLower(key)]
+	return ok
+}
+
+// maskJSON 遮盖 JSON 值，sensitive 表示所属字段需整体遮盖（不受遮盖策略影响）
+// 未改动的值按原文输出，changed 为 false 时返回 raw 本身
+func (m *masker) maskJSON(raw json.RawMessage, sensitive bool) (out []byte, changed bool) {
+	if len(raw) == 0 {
+		return raw, false
+	}
+
+	switch raw[0] {
+	case '{':
+		var buf bytes.Buffer
+		buf.WriteByte('{')
+		n := 0
+		err := decodeLine(raw, func(key string, v json.RawMessage) {
+			if n > 0 {
+				buf.WriteByte(',')
+			}
+			n++
+			appendJSONString(&buf, key)
+			buf.WriteByte(':')
+			if _, skip := skipDetectKeys[key]; skip && !sensitive {
+				buf.Write(v)
+				return
+			}
+			mv, c := m.maskJSON(v, sensitive || m.sensitiveKey(key))
+			buf.Write(mv)
+			changed = changed || c
+		})
+		if err != nil || !changed {
+			return raw, false
+		}
+		buf.WriteByte('}')
+		return buf.Bytes(), true
+	case '[':
+		var items []json.RawMessage
+		if err := json.Unmarshal(raw, &items); err != nil {
+			return raw, false
+		}
+		var buf bytes.Buffer
+		buf.WriteByte('[')
+		for i, item := range items {
+			if i > 0 {
+				buf.WriteByte(',')
+			}
+			mv, c := m.maskJSON(item, sensitive)
+			buf.Write(mv)
+			changed = changed || c
+		}
+		if !changed {
+			return raw, false
+		}
+		buf.WriteByte(']')
+		return buf.Bytes(), true
+	case '"':
+		var s string
+		if err := json.Unmarshal(raw, &s); err != nil {
+			return raw, false
+		}
+		if sensitive {
+			s = MaskedValue
+		} else if masked := m.detect(s); masked != s {
+			s = masked
+		} else {
+			return raw, false
+		}
+		var buf bytes.Buffer
+		appendJSONString(&buf, s)
+		return buf.Bytes(), true
+	case 'n', 't', 'f': // null、布尔
+		if raw[0] == 'n' || !sensitive {
+			return raw, false
+		}
+	default: // 数字同样识别，如 Int64 记录的手机号、卡号
+		if !sensitive {
+			s := string(raw)
+			masked := m.detect(s)
+			if masked == s {
+				return raw, false
+			}
+			var buf bytes.Buffer
+			appendJSONString(&buf, masked)
+			return buf.Bytes(), true
+		}
+	}
+	var buf bytes.Buffer
+	appendJSONString(&buf, MaskedValue)
+	return buf.Bytes(), true
+}
+
+// appendJSONString 写出 JSON 字符串，不转义 HTML 字符，与 zerolog 的编码保持一致
+func appendJSONString(buf *bytes.Buffer, s string) {
+	enc := json.NewEncoder(buf)
+	enc.SetEscapeHTML(false)
+	_ = enc.Encode(s)
+	buf.Truncate(buf.Len() - 1) // Encode 追加的换行
+}
+
+// maskWriter 输出前遮盖 JSON 行，位于格式转换与多路输出之前
+type maskWriter struct {
+	m   *masker
+	out io.Writer
+}
+
+func (w maskWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w maskWriter) WriteLevel(level Level, p []byte) (int, error) {
+	line := bytes.TrimRight(p, "\n")
+	masked := p
+	if out, changed := w.m.maskJSON(line, false); changed {
+		masked = append(out, '\n')
+	}
+
+	var err error
+	if lw, ok := w.out.(zerolog.LevelWriter); ok {
+		_, err = lw.WriteLevel(level, masked)
+	} else {
+		_, err = w.out.Write(masked)
+	}
+	if err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w maskWriter) Sync() error {
+	return syncWriter(w.out)
+}
+
+func (w maskWriter) Close() error {
+	return closeWriter(w.out)
+}
+
+// luhnValid 银行卡号 Luhn 校验，忽略空格与连字符
+func luhnValid(s string) bool {
+	sum, n := 0, 0
+	for i := len(s) - 1; i >= 0; i-- {
+		c := s[i]
+		if c == ' ' || c == '-' {
+			continue
+		}
+		d := int(c - '0')
+		if n%2 == 1 {
+			if d *= 2; d > 9 {
+				d -= 9
+			}
+		}
+		sum += d
+		n++
+	}
+	return n >= 13 && sum%10 == 0
+}
@@ -0,0 +1,67 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNamed(t *testing.T) {
+	log := Named("order.service")
+	assert.Same(t, log, Named("order.service"))
+
+	var buf bytes.Buffer
+	Init(WithOutput(&buf), WithLevels(LevelConfig{
+		Level:      "info",
+		Components: map[string]string{"gorm": "warn", "order": "error", "payment": "debug"},
+	}))
+	defer Init()
+	ctx := context.Background()
+
+	// 包级变量在 Init 后切换到新的输出与级别
+	log.Warn(ctx).Msg("order warn")
+	log.Error(ctx).Msg("order error")
+	Named("gorm").Info(ctx).Msg("gorm info")
+	Named("payment").Named("callback").Debug(ctx).Msg("payment debug")
+	Info(ctx, "root info")
+
+	out := buf.String()
+	assert.NotContains(t, out, "order warn")
+	assert.NotContains(t, out, "gorm info")
+	assert.Contains(t, out, `"logger":"order.service"`)
+	assert.Contains(t, out, `"logger":"payment.callback"`)
+	assert.Contains(t, out, "root info")
+	for _, line := range strings.Split(strings.TrimSpace(out), "\n") {
+		assert.LessOrEqual(t, strings.Count(line, `"logger"`), 1, line)
+	}
+
+	assert.Panics(t, func() { NewLogger(WithLevels(LevelConfig{Components: map[string]string{"x": "loud"}})) })
+}
+
+func TestNamedConcurrentInit(t *testing.T) {
+	log := Named("race.svc")
+	defer ReplaceGlobal(NewLogger(WithOutput(&bytes.Buffer{})))()
+	ctx := context.Background()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		for i := 0; i < 200; i++ {
+			log.Info(ctx).Int("i", i).Msg("tick")
+			_ = log.Name()
+		}
+	}()
+	for i := 0; i < 50; i++ {
+		Init(WithOutput(&bytes.Buffer{}))
+	}
+	<-done
+
+	var buf bytes.Buffer
+	Init(WithOutput(&buf))
+	log.Info(ctx).Msg("after")
+	assert.Contains(t, buf.String(), `"logger":"race.svc"`)
+	assert.Same(t, log, Named("race.svc"))
+}
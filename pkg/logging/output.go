@@ -0,0 +1,253 @@
+package logging
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+	"gopkg.in/natefinch/lumberjack.v2"
+)
+
+// 按时间滚动周期
+const (
+	RotateDaily  = "daily"
+	RotateHourly = "hourly"
+)
+
+// FileConfig 滚动文件配置，零值字段使用 lumberjack 默认值（100MB、不限数量、不限天数、不压缩）
+type FileConfig struct {
+	Filename   string `mapstructure:"filename" json:"filename" yaml:"filename"`
+	MaxSize    int    `mapstructure:"max_size" json:"max_size" yaml:"max_size"`          // 单个文件最大 MB
+	MaxBackups int    `mapstructure:"max_backups" json:"max_backups" yaml:"max_backups"` // 最多保留几个旧文件
+	MaxAge     int    `mapstructure:"max_age" json:"max_age" yaml:"max_age"`             // 旧文件最长保存天数
+	Compress   bool   `mapstructure:"compress" json:"compress" yaml:"compress"`          // 是否 gzip 压缩旧文件
+	LocalTime  bool   `mapstructure:"local_time" json:"local_time" yaml:"local_time"`    // 旧文件名使用本地时间
+	Rotate     string `mapstructure:"rotate" json:"rotate" yaml:"rotate"`                // 按时间滚动：daily、hourly，为空仅按大小滚动
+}
+
+// DefaultFileConfig 默认文件配置：200MB、保留 5 个、14 天、压缩
+func DefaultFileConfig(filename string) FileConfig {
+	return FileConfig{
+		Filename:   filename,
+		MaxSize:    200,
+		MaxBackups: 5,
+		MaxAge:     14,
+		Compress:   true,
+	}
+}
+
+// NewFileWriter 创建滚动文件写入器（同步），一般通过 WithFile/FileSink 使用
+func NewFileWriter(cfg FileConfig) (io.WriteCloser, error) {
+	if cfg.Filename == "" {
+		return nil, errors.New("logging: filename required")
+	}
+	lj := &lumberjack.Logger{
+		Filename:   cfg.Filename,
+		MaxSize:    cfg.MaxSize,
+		MaxBackups: cfg.MaxBackups,
+		MaxAge:     cfg.MaxAge,
+		Compress:   cfg.Compress,
+		LocalTime:  cfg.LocalTime,
+	}
+
+	var period time.Duration
+	switch cfg.Rotate {
+	case "":
+		return lj, nil
+	case RotateDaily:
+		period = 24 * time.Hour
+	case RotateHourly:
+		period = time.Hour
+	default:
+		return nil, fmt.Errorf("logging: invalid rotate %q", cfg.Rotate)
+	}
+	r := &timeRotator{lj: lj, period: period, now: time.Now}
+	r.next = r.boundary(r.now())
+	return r, nil
+}
+
+// timeRotator 在跨越整点/零点后的首次写入时滚动文件
+type timeRotator struct {
+	lj     *lumberjack.Logger
+	period time.Duration
+	now    func() time.Time
+
+	mu   sync.Mutex
+	next time.Time
+}
+
+func (r *timeRotator) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	if now := r.now(); !now.Before(r.next) {
+		r.next = r.boundary(now)
+		if err := r.lj.Rotate(); err != nil {
+			r.mu.Unlock()
+			return 0, err
+		}
+	}
+	r.mu.Unlock()
+	return r.lj.Write(p)
+}
+
+func (r *timeRotator) Close() error {
+	return r.lj.Close()
+}
+
+// boundary 返回 t 之后的下一个滚动时间点（按本地时区对齐）
+func (r *timeRotator) boundary(t time.Time) time.Time {
+	if r.period == time.Hour {
+		return t.Truncate(time.Hour).Add(time.Hour)
+	}
+	y, m, d := t.Date()
+	return time.Date(y, m, d+1, 0, 0, 0, 0, t.Location())
+}
+
+// Sink 日志输出目标，仅写入不低于 MinLevel 的日志，MinLevel 零值为 DebugLevel 即不限制
+type Sink struct {
+	Writer   io.Writer
+	MinLevel Level
+	Format   string // 为空时使用 WithFormat 设置的格式
+}
+
+// FileSink 创建异步滚动文件输出，如 FileSink(FileConfig{Filename: "error.log"}, ErrorLevel) 单独记录错误日志
+func FileSink(cfg FileConfig, minLevel Level, opts ...AsyncOption) (Sink, error) {
+	w, err := NewFileWriter(cfg)
+	if err != nil {
+		return Sink{}, err
+	}
+	return Sink{Writer: NewAsyncWriter(w, opts...), MinLevel: minLevel}, nil
+}
+
+// WithSinks 设置全部输出目标，替换默认的 Stdout
+func WithSinks(sinks ...Sink) Option {
+	return func(c *config) {
+		c.sinks = append([]Sink(nil), sinks...)
+	}
+}
+
+// WithSink 追加输出目标，如 WithSink(os.Stdout, DebugLevel)
+func WithSink(w io.Writer, minLevel Level) Option {
+	return func(c *config) {
+		c.sinks = append(c.sinks, Sink{Writer: w, MinLevel: minLevel})
+	}
+}
+
+// WithFile 以异步滚动文件作为输出，配置非法时 panic（启动阶段暴露错误）
+func WithFile(cfg FileConfig, opts ...AsyncOption) Option {
+	return func(c *config) {
+		sink, err := FileSink(cfg, DebugLevel, opts...)
+		if err != nil {
+			panic(err)
+		}
+		c.sinks = []Sink{sink}
+	}
+}
+
+// buildOutput 按格式包装并合并输出目标，单个无级别限制的目标直接使用，启用遮盖时在其外层处理，最外层去除重复 key
+func buildOutput(cfg *config) (io.Writer, error) {
+	sinks := make([]Sink, len(cfg.sinks))
+	for i, s := range cfg.sinks {
+		format := s.Format
+		if format == "" {
+			format = cfg.format
+		}
+		w, err := newFormatWriter(s.Writer, format, cfg.noColor)
+		if err != nil {
+			return nil, err
+		}
+		s.Writer = w
+		sinks[i] = s
+	}
+
+	var out io.Writer = multiSink(sinks)
+	if len(sinks) == 1 && sinks[0].MinLevel <= DebugLevel {
+		out = sinks[0].Writer
+	}
+
+	if cfg.mask != nil {
+		m, err := newMasker(cfg.mask)
+		if err != nil {
+			return nil, err
+		}
+		out = maskWriter{m: m, out: out}
+	}
+	return dedupWriter{out: out}, nil
+}
+
+// asyncWriters 收集输出目标中的 AsyncWriter（含 WithFile/FileSink 创建的）
+func asyncWriters(sinks []Sink) []*AsyncWriter {
+	var ws []*AsyncWriter
+	for _, s := range sinks {
+		if w, ok := s.Writer.(*AsyncWriter); ok {
+			ws = append(ws, w)
+		}
+	}
+	return ws
+}
+
+// multiSink 按级别分发到多个输出
+type multiSink []Sink
+
+func (m multiSink) Write(p []byte) (int, error) {
+	return m.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (m multiSink) WriteLevel(level Level, p []byte) (int, error) {
+	var errs []error
+	for _, s := range m {
+		if level < s.MinLevel {
+			continue
+		}
+		var err error
+		if lw, ok := s.Writer.(zerolog.LevelWriter); ok {
+			_, err = lw.WriteLevel(level, p)
+		} else {
+			_, err = s.Writer.Write(p)
+		}
+		if err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return len(p), errors.Join(errs...)
+}
+
+func (m multiSink) Sync() error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, syncWriter(s.Writer))
+	}
+	return errors.Join(errs...)
+}
+
+func (m multiSink) Close() error {
+	var errs []error
+	for _, s := range m {
+		errs = append(errs, closeWriter(s.Writer))
+	}
+	return errors.Join(errs...)
+}
+
+// syncWriter 刷出缓冲，Stdout/Stderr 无缓冲且在管道上 fsync 会报错，跳过
+func syncWriter(w io.Writer) error {
+	if isStdStream(w) {
+		return nil
+	}
+	if s, ok := w.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+// closeWriter 刷出并关闭，Stdout/Stderr 不会被关闭
+func closeWriter(w io.Writer) error {
+	if isStdStream(w) {
+		return nil
+	}
+	if c, ok := w.(io.Closer); ok {
+		return c.Close()
+	}
+	return syncWriter(w)
+}
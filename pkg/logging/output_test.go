@@ -0,0 +1,63 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMultiSink(t *testing.T) {
+	dir := t.TempDir()
+	errSink, err := FileSink(FileConfig{Filename: filepath.Join(dir, "error.log")}, ErrorLevel)
+	require.NoError(t, err)
+
+	var app bytes.Buffer
+	l := NewLogger(WithLevel(DebugLevel), WithOutput(&app), WithSink(errSink.Writer, errSink.MinLevel))
+	ctx := context.Background()
+	l.Debug(ctx).Msg("debug line")
+	l.Error(ctx).Msg("error line")
+	require.NoError(t, l.Close())
+
+	assert.Contains(t, app.String(), "debug line")
+	assert.Contains(t, app.String(), "error line")
+
+	data, err := os.ReadFile(filepath.Join(dir, "error.log"))
+	require.NoError(t, err)
+	assert.NotContains(t, string(data), "debug line")
+	assert.Contains(t, string(data), "error line")
+}
+
+func TestTimeRotator(t *testing.T) {
+	dir := t.TempDir()
+	w, err := NewFileWriter(FileConfig{Filename: filepath.Join(dir, "app.log"), Rotate: RotateHourly})
+	require.NoError(t, err)
+	r := w.(*timeRotator)
+
+	now := time.Date(2024, 5, 1, 10, 59, 0, 0, time.Local)
+	r.now = func() time.Time { return now }
+	r.next = r.boundary(now)
+	assert.Equal(t, time.Date(2024, 5, 1, 11, 0, 0, 0, time.Local), r.next)
+
+	_, err = w.Write([]byte("before\n"))
+	require.NoError(t, err)
+	now = now.Add(2 * time.Minute)
+	_, err = w.Write([]byte("after\n"))
+	require.NoError(t, err)
+	require.NoError(t, w.Close())
+
+	files, err := os.ReadDir(dir)
+	require.NoError(t, err)
+	assert.Len(t, files, 2, "跨越整点后应滚动出一个旧文件")
+	data, err := os.ReadFile(filepath.Join(dir, "app.log"))
+	require.NoError(t, err)
+	assert.Equal(t, "after\n", string(data))
+
+	_, err = NewFileWriter(FileConfig{Filename: "x.log", Rotate: "weekly"})
+	assert.Error(t, err)
+}
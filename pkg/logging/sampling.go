@@ -0,0 +1,183 @@
+package logging
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+const (
+	maxRateLimitKeys    = 10000 // 单个周期内最多跟踪的消息数，超出后新消息不限流
+	maxDroppedKeys      = 10000 // 单个周期内最多统计的被抑制消息数，超出后仅计入总数
+	maxSummaryMessages  = 10    // 汇总日志中列出的被抑制消息数
+	defaultSampleWindow = time.Second
+)
+
+type levelSampling struct {
+	first      int
+	thereafter int
+}
+
+type samplingConfig struct {
+	interval  time.Duration
+	levels    map[Level]levelSampling
+	rateLimit int // 每条消息每周期最多输出条数，0 表示不限
+}
+
+func (c *config) sampling() *samplingConfig {
+	if c.sample == nil {
+		c.sample = &samplingConfig{interval: defaultSampleWindow, levels: make(map[Level]levelSampling)}
+	}
+	return c.sample
+}
+
+// WithSampling 按级别采样：每个周期内该级别前 first 条全部输出，之后每 thereafter 条输出 1 条（为 0 时全部丢弃）
+func WithSampling(level Level, first, thereafter int) Option {
+	return func(c *config) {
+		c.sampling().levels[level] = levelSampling{first: first, thereafter: thereafter}
+	}
+}
+
+// WithRateLimit 按消息限流：相同 message 每个周期最多输出 limit 条
+func WithRateLimit(limit int) Option {
+	return func(c *config) {
+		if limit > 0 {
+			c.sampling().rateLimit = limit
+		}
+	}
+}
+
+// WithSampleInterval 设置采样/限流周期，默认 1s
+func WithSampleInterval(d time.Duration) Option {
+	return func(c *config) {
+		if d > 0 {
+			c.sampling().interval = d
+		}
+	}
+}
+
+// sampler 以 Hook 形式在输出前丢弃日志，周期结束后输出一条被抑制数量的汇总日志
+// Fatal、Panic 不参与采样
+type sampler struct {
+	cfg     samplingConfig
+	summary logger // 不带采样 Hook，用于输出汇总
+	now     func() time.Time
+
+	mu          sync.Mutex
+	windowStart time.Time
+	levelCount  map[Level]int
+	msgCount    map[string]int
+	dropped     map[Level]int
+	droppedMsg  map[string]int
+}
+
+func newSampler(cfg samplingConfig, summary logger) *sampler {
+	s := &sampler{cfg: cfg, summary: summary, now: time.Now}
+	s.reset(s.now())
+	return s
+}
+
+func (s *sampler) reset(now time.Time) {
+	s.windowStart = now
+	s.levelCount = make(map[Level]int)
+	s.msgCount = make(map[string]int)
+	s.dropped = make(map[Level]int)
+	s.droppedMsg = make(map[string]int)
+}
+
+func (s *sampler) Run(e *Event, level Level, msg string) {
+	if level >= FatalLevel {
+		return
+	}
+
+	now := s.now()
+	s.mu.Lock()
+	var dropped map[Level]int
+	var droppedMsg map[string]int
+	if now.Sub(s.windowStart) >= s.cfg.interval {
+		dropped, droppedMsg = s.dropped, s.droppedMsg
+		s.reset(now)
+	}
+	keep := s.allow(level, msg)
+	if !keep {
+		s.dropped[level]++
+		if n, tracked := s.droppedMsg[msg]; tracked || len(s.droppedMsg) < maxDroppedKeys {
+			s.droppedMsg[msg] = n + 1
+		}
+	}
+	s.mu.Unlock()
+
+	if len(dropped) > 0 {
+		s.report(dropped, droppedMsg)
+	}
+	if !keep {
+		e.Discard()
+	}
+}
+
+// allow 需持有锁
+func (s *sampler) allow(level Level, msg string) bool {
+	if s.cfg.rateLimit > 0 {
+		n, tracked := s.msgCount[msg]
+		if tracked || len(s.msgCount) < maxRateLimitKeys {
+			s.msgCount[msg] = n + 1
+			if n >= s.cfg.rateLimit {
+				return false
+			}
+		}
+	}
+
+	ls, ok := s.cfg.levels[level]
+	if !ok {
+		return true
+	}
+	n := s.levelCount[level] + 1
+	s.levelCount[level] = n
+	if n <= ls.first {
+		return true
+	}
+	return ls.thereafter > 0 && (n-ls.first)%ls.thereafter == 0
+}
+
+// flush 输出当前周期的汇总，用于 Sync/Close
+func (s *sampler) flush() {
+	s.mu.Lock()
+	dropped, droppedMsg := s.dropped, s.droppedMsg
+	s.reset(s.now())
+	s.mu.Unlock()
+
+	if len(dropped) > 0 {
+		s.report(dropped, droppedMsg)
+	}
+}
+
+func (s *sampler) report(dropped map[Level]int, droppedMsg map[string]int) {
+	total := 0
+	byLevel := zerolog.Dict()
+	for level, n := range dropped {
+		total += n
+		byLevel.Int(level.String(), n)
+	}
+
+	msgs := make([]string, 0, len(droppedMsg))
+	for msg := range droppedMsg {
+		msgs = append(msgs, msg)
+	}
+	sort.Slice(msgs, func(i, j int) bool { return droppedMsg[msgs[i]] > droppedMsg[msgs[j]] })
+	if len(msgs) > maxSummaryMessages {
+		msgs = msgs[:maxSummaryMessages]
+	}
+	// 消息作为值输出，使遮盖等处理对其生效
+	byMsg := zerolog.Arr()
+	for _, msg := range msgs {
+		byMsg.Dict(zerolog.Dict().Str(zerolog.MessageFieldName, msg).Int("count", droppedMsg[msg]))
+	}
+
+	s.summary.WithLevel(WarnLevel).
+		Int("suppressed", total).
+		Dict("suppressed_levels", byLevel).
+		Array("suppressed_messages", byMsg).
+		Msg("log messages suppressed by sampling")
+}
@@ -0,0 +1,288 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"time"
+
+	"github.com/rs/zerolog"
+)
+
+type slogConfig struct {
+	addSource bool
+}
+
+type SlogOption func(*slogConfig)
+
+// WithSlogSource 输出 slog 调用处的 caller
+func WithSlogSource() SlogOption {
+	return func(c *slogConfig) {
+		c.addSource = true
+	}
+}
+
+// SlogHandler 基于 Logger 的 slog.Handler，共享其输出、级别及 ctx 中的 fields
+type SlogHandler struct {
+	l    *Logger // 为 nil 时每次输出取 L()，使后续 Init 生效
+	cfg  slogConfig
+	goas []groupOrAttrs // WithGroup/WithAttrs 调用链
+}
+
+// groupOrAttrs 仅其中一个字段有值
+type groupOrAttrs struct {
+	group string
+	attrs []slog.Attr
+}
+
+// NewSlogHandler 创建 slog.Handler，l 为 nil 时使用 L()
+func NewSlogHandler(l *Logger, opts ...SlogOption) *SlogHandler {
+	h := &SlogHandler{l: l}
+	for _, opt := range opts {
+		opt(&h.cfg)
+	}
+	return h
+}
+
+// SetSlogDefault 将 slog 默认 Logger（及标准库 log）输出到 L()
+func SetSlogDefault(opts ...SlogOption) {
+	slog.SetDefault(slog.New(NewSlogHandler(nil, opts...)))
+}
+
+func (h *SlogHandler) logger() *Logger {
+	if h.l != nil {
+		return h.l
+	}
+	return L()
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger().Enabled(fromSlogLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, r slog.Record) error {
+	e := h.logger().newEvent(ctx, fromSlogLevel(r.Level))
+	if e == nil {
+		return nil
+	}
+	if h.cfg.addSource && r.PC != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+		e.Str(zerolog.CallerFieldName, callerShortFunc(frame.PC, frame.File, frame.Line))
+	}
+	h.write(e, 0, r)
+	e.Msg(r.Message)
+	return nil
+}
+
+// write 从第 i 个 WithGroup/WithAttrs 开始写入，遇到 group 时后续字段嵌套到该 group 中
+func (h *SlogHandler) write(e *Event, i int, r slog.Record) {
+	for ; i < len(h.goas); i++ {
+		if g := h.goas[i].group; g != "" {
+			d := zerolog.Dict()
+			h.write(d, i+1, r)
+			e.Dict(g, d)
+			return
+		}
+		for _, a := range h.goas[i].attrs {
+			addSlogAttr(e, a)
+		}
+	}
+	r.Attrs(func(a slog.Attr) bool {
+		addSlogAttr(e, a)
+		return true
+	})
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	if len(attrs) == 0 {
+		return h
+	}
+	return h.with(groupOrAttrs{attrs: attrs})
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return h.with(groupOrAttrs{group: name})
+}
+
+func (h *SlogHandler) with(goa groupOrAttrs) *SlogHandler {
+	nh := *h
+	nh.goas = make([]groupOrAttrs, len(h.goas), len(h.goas)+1)
+	copy(nh.goas, h.goas)
+	nh.goas = append(nh.goas, goa)
+	return &nh
+}
+
+func addSlogAttr(e *Event, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+
+	switch a.Value.Kind() {
+	case slog.KindString:
+		e.Str(a.Key, a.Value.String())
+	case slog.KindInt64:
+		e.Int64(a.Key, a.Value.Int64())
+	case slog.KindUint64:
+		e.Uint64(a.Key, a.Value.Uint64())
+	case slog.KindFloat64:
+		e.Float64(a.Key, a.Value.Float64())
+	case slog.KindBool:
+		e.Bool(a.Key, a.Value.Bool())
+	case slog.KindDuration:
+		e.Dur(a.Key, a.Value.Duration())
+	case slog.KindTime:
+		e.Time(a.Key, a.Value.Time())
+	case slog.KindGroup:
+		attrs := a.Value.Group()
+		if len(attrs) == 0 {
+			return
+		}
+		if a.Key == "" { // 空 key 的 group 内联
+			for _, ga := range attrs {
+				addSlogAttr(e, ga)
+			}
+			return
+		}
+		d := zerolog.Dict()
+		for _, ga := range attrs {
+			addSlogAttr(d, ga)
+		}
+		e.Dict(a.Key, d)
+	default:
+		if err, ok := a.Value.Any().(error); ok {
+			e.AnErr(a.Key, err)
+			return
+		}
+		e.Interface(a.Key, a.Value.Any())
+	}
+}
+
+// fromSlogLevel slog 级别按区间映射，如 slog.LevelInfo+2 视为 Info
+func fromSlogLevel(l slog.Level) Level {
+	switch {
+	case l < slog.LevelInfo:
+		return DebugLevel
+	case l < slog.LevelWarn:
+		return InfoLevel
+	case l < slog.LevelError:
+		return WarnLevel
+	default:
+		return ErrorLevel
+	}
+}
+
+func toSlogLevel(l Level) slog.Level {
+	switch l {
+	case zerolog.TraceLevel, DebugLevel:
+		return slog.LevelDebug
+	case WarnLevel:
+		return slog.LevelWarn
+	case ErrorLevel:
+		return slog.LevelError
+	case FatalLevel, PanicLevel:
+		return slog.LevelError + 4
+	default:
+		return slog.LevelInfo
+	}
+}
+
+// WithSlogHandler 以 slog.Handler 作为输出，Logger 的字段、级别与 ctx fields 转为 slog.Record
+// 注意 h 不能是输出到本 Logger 的 SlogHandler（如 SetSlogDefault 后的 slog.Default().Handler()），否则循环调用
+func WithSlogHandler(h slog.Handler) Option {
+	return func(c *config) {
+		c.sinks = []Sink{{Writer: slogWriter{h: h}, Format: FormatJSON}}
+	}
+}
+
+// slogWriter 将 JSON 行解析为 slog.Record 交给 handler，时间取写入时刻
+type slogWriter struct {
+	h slog.Handler
+}
+
+func (w slogWriter) Write(p []byte) (int, error) {
+	return w.WriteLevel(zerolog.NoLevel, p)
+}
+
+func (w slogWriter) WriteLevel(_ Level, p []byte) (int, error) {
+	var (
+		level = slog.LevelInfo
+		msg   string
+		attrs []slog.Attr
+	)
+	err := decodeLine(p, func(key string, raw json.RawMessage) {
+		switch key {
+		case zerolog.TimestampFieldName:
+		case zerolog.LevelFieldName:
+			var s string
+			if json.Unmarshal(raw, &s) == nil {
+				if lvl, err := zerolog.ParseLevel(s); err == nil {
+					level = toSlogLevel(lvl)
+				}
+			}
+		case zerolog.MessageFieldName:
+			_ = json.Unmarshal(raw, &msg)
+		default:
+			attrs = append(attrs, slog.Attr{Key: key, Value: slogValue(raw)})
+		}
+	})
+	if err != nil {
+		msg = string(p)
+	}
+
+	ctx := context.Background()
+	if !w.h.Enabled(ctx, level) {
+		return len(p), nil
+	}
+	r := slog.NewRecord(time.Now(), level, msg, 0)
+	r.AddAttrs(attrs...)
+	if err := w.h.Handle(ctx, r); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+func (w slogWriter) Sync() error {
+	if s, ok := w.h.(interface{ Sync() error }); ok {
+		return s.Sync()
+	}
+	return nil
+}
+
+func (w slogWriter) Close() error {
+	if c, ok := w.h.(io.Closer); ok {
+		return c.Close()
+	}
+	return nil
+}
+
+func slogValue(raw json.RawMessage) slog.Value {
+	var v any
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	if err := dec.Decode(&v); err != nil {
+		return slog.StringValue(string(raw))
+	}
+
+	switch v := v.(type) {
+	case string:
+		return slog.StringValue(v)
+	case bool:
+		return slog.BoolValue(v)
+	case json.Number:
+		if n, err := strconv.ParseInt(v.String(), 10, 64); err == nil {
+			return slog.Int64Value(n)
+		}
+		f, _ := v.Float64()
+		return slog.Float64Value(f)
+	default:
+		return slog.AnyValue(v)
+	}
+}
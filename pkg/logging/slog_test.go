@@ -0,0 +1,60 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	l := NewLogger(WithOutput(&buf)).Named("thirdparty")
+	sl := slog.New(NewSlogHandler(l, WithSlogSource()))
+	ctx := WithFields(context.Background(), Str("request_id", "r1"))
+
+	sl.DebugContext(ctx, "hidden")
+	assert.Empty(t, buf.String())
+
+	sl.With("client", "s3").WithGroup("req").With("id", 7).
+		WarnContext(ctx, "retry", "attempt", 2, slog.Group("backoff", "wait", time.Second), "err", errors.New("timeout"))
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "warn", m["level"])
+	assert.Equal(t, "retry", m["message"])
+	assert.Equal(t, "thirdparty", m["logger"])
+	assert.Equal(t, "r1", m["request_id"])
+	assert.Equal(t, "s3", m["client"])
+	assert.Equal(t, map[string]any{
+		"id":      7.0,
+		"attempt": 2.0,
+		"backoff": map[string]any{"wait": 1000.0},
+		"err":     "timeout",
+	}, m["req"])
+	assert.Contains(t, m["caller"], "logging/slog_test.go:")
+}
+
+func TestWithSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	h := slog.NewJSONHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug})
+	l := NewLogger(WithSlogHandler(h), WithLevel(DebugLevel))
+	ctx := WithFields(context.Background(), Str("request_id", "r1"))
+
+	l.Error(ctx).Int("code", 500).EmbedObject(Dict("user", Int("id", 1))).Msg("failed")
+
+	var m map[string]any
+	require.NoError(t, json.Unmarshal(buf.Bytes(), &m))
+	assert.Equal(t, "ERROR", m["level"])
+	assert.Equal(t, "failed", m["msg"])
+	assert.Equal(t, "r1", m["request_id"])
+	assert.Equal(t, 500.0, m["code"])
+	assert.Equal(t, map[string]any{"id": 1.0}, m["user"])
+	assert.NotContains(t, m, "message")
+}
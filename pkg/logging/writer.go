@@ -0,0 +1,197 @@
+package logging
+
+import (
+	"bufio"
+	"errors"
+	"io"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ErrWriterClosed 写入已关闭的 AsyncWriter
+var ErrWriterClosed = errors.New("logging: writer closed")
+
+const (
+	defaultQueueSize     = 4096
+	defaultBufferSize    = 256 << 10 // 256KB
+	defaultFlushInterval = time.Second
+)
+
+type asyncConfig struct {
+	queueSize     int
+	bufferSize    int
+	flushInterval time.Duration
+	block         bool
+}
+
+type AsyncOption func(*asyncConfig)
+
+// WithQueueSize 设置队列长度（行数），默认 4096
+func WithQueueSize(n int) AsyncOption {
+	return func(c *asyncConfig) {
+		if n > 0 {
+			c.queueSize = n
+		}
+	}
+}
+
+// WithBufferSize 设置写缓冲大小，默认 256KB
+func WithBufferSize(n int) AsyncOption {
+	return func(c *asyncConfig) {
+		if n > 0 {
+			c.bufferSize = n
+		}
+	}
+}
+
+// WithFlushInterval 设置定时刷盘间隔，默认 1s
+func WithFlushInterval(d time.Duration) AsyncOption {
+	return func(c *asyncConfig) {
+		if d > 0 {
+			c.flushInterval = d
+		}
+	}
+}
+
+// WithBlockOnFull 队列满时阻塞等待，默认丢弃并计数
+func WithBlockOnFull() AsyncOption {
+	return func(c *asyncConfig) {
+		c.block = true
+	}
+}
+
+// AsyncWriter 并发安全的异步写入器：日志行进入有界队列，由单独的 goroutine 缓冲写入并定时刷盘
+type AsyncWriter struct {
+	out     io.Writer
+	buf     *bufio.Writer
+	cfg     asyncConfig
+	queue   chan []byte
+	syncReq chan chan error
+	done    chan struct{}
+	dropped atomic.Uint64
+
+	mu     sync.RWMutex // 保护 closed 与 queue 的关闭
+	closed bool
+	err    error // 关闭时的刷盘错误
+}
+
+// NewAsyncWriter 创建异步写入器，out 为 io.Closer 时 Close 会一并关闭
+func NewAsyncWriter(out io.Writer, opts ...AsyncOption) *AsyncWriter {
+	cfg := asyncConfig{
+		queueSize:     defaultQueueSize,
+		bufferSize:    defaultBufferSize,
+		flushInterval: defaultFlushInterval,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	w := &AsyncWriter{
+		out:     out,
+		buf:     bufio.NewWriterSize(out, cfg.bufferSize),
+		cfg:     cfg,
+		queue:   make(chan []byte, cfg.queueSize),
+		syncReq: make(chan chan error),
+		done:    make(chan struct{}),
+	}
+	go w.run()
+	return w
+}
+
+// Write 写入一行日志，p 会被复制（zerolog 会复用其缓冲区）
+func (w *AsyncWriter) Write(p []byte) (int, error) {
+	w.mu.RLock()
+	defer w.mu.RUnlock()
+	if w.closed {
+		return 0, ErrWriterClosed
+	}
+
+	line := make([]byte, len(p))
+	copy(line, p)
+
+	if w.cfg.block {
+		w.queue <- line
+		return len(p), nil
+	}
+	select {
+	case w.queue <- line:
+	default:
+		w.dropped.Add(1)
+	}
+	return len(p), nil
+}
+
+// Dropped 队列满被丢弃的日志行数
+func (w *AsyncWriter) Dropped() uint64 {
+	return w.dropped.Load()
+}
+
+// Sync 写出队列中已有的日志并刷盘
+func (w *AsyncWriter) Sync() error {
+	w.mu.RLock()
+	if w.closed {
+		w.mu.RUnlock()
+		return nil
+	}
+	ack := make(chan error, 1)
+	w.syncReq <- ack
+	w.mu.RUnlock()
+	return <-ack
+}
+
+// Close 写出全部日志并关闭，重复调用返回首次关闭的结果
+func (w *AsyncWriter) Close() error {
+	w.mu.Lock()
+	if w.closed {
+		w.mu.Unlock()
+		<-w.done
+		return w.err
+	}
+	w.closed = true
+	close(w.queue)
+	w.mu.Unlock()
+
+	<-w.done
+	return w.err
+}
+
+func (w *AsyncWriter) run() {
+	defer close(w.done)
+
+	ticker := time.NewTicker(w.cfg.flushInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case line, ok := <-w.queue:
+			if !ok {
+				w.err = w.buf.Flush()
+				if c, ok := w.out.(io.Closer); ok {
+					w.err = errors.Join(w.err, c.Close())
+				}
+				return
+			}
+			_, _ = w.buf.Write(line)
+		case ack := <-w.syncReq:
+			ack <- w.drain()
+		case <-ticker.C:
+			_ = w.buf.Flush()
+		}
+	}
+}
+
+// drain 写出当前队列中的日志并刷盘
+func (w *AsyncWriter) drain() error {
+	for {
+		select {
+		case line, ok := <-w.queue:
+			if !ok {
+				return w.buf.Flush()
+			}
+			_, _ = w.buf.Write(line)
+		default:
+			return w.buf.Flush()
+		}
+	}
+}
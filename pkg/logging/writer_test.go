@@ -0,0 +1,110 @@
+package logging
+
+import (
+	"bytes"
+	"context"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// blockingWriter 在 release 关闭前阻塞写入，用于填满队列
+type blockingWriter struct {
+	mu      sync.Mutex
+	buf     bytes.Buffer
+	release chan struct{}
+	closed  bool
+}
+
+func (w *blockingWriter) Write(p []byte) (int, error) {
+	<-w.release
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.Write(p)
+}
+
+func (w *blockingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	w.closed = true
+	return nil
+}
+
+func (w *blockingWriter) String() string {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.buf.String()
+}
+
+func TestAsyncWriter(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	close(out.release)
+	w := NewAsyncWriter(out, WithBufferSize(16))
+
+	l := NewLogger(WithOutput(w))
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 50; j++ {
+				l.Info(context.Background()).Int("j", j).Msg("concurrent")
+			}
+		}()
+	}
+	wg.Wait()
+
+	require.NoError(t, l.Sync())
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	assert.Len(t, lines, 400)
+	for _, line := range lines {
+		assert.True(t, strings.HasPrefix(line, "{") && strings.HasSuffix(line, "}"), line)
+	}
+
+	require.NoError(t, l.Close())
+	assert.True(t, out.closed)
+	_, err := w.Write([]byte("late\n"))
+	assert.ErrorIs(t, err, ErrWriterClosed)
+	assert.NoError(t, w.Close())
+}
+
+func TestAsyncWriterDrop(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	w := NewAsyncWriter(out, WithQueueSize(2), WithBufferSize(1))
+
+	for i := 0; i < 10; i++ {
+		_, err := w.Write([]byte("line\n"))
+		require.NoError(t, err)
+	}
+	// 后台 goroutine 最多取走一行阻塞在写入上，队列中最多保留 2 行
+	assert.GreaterOrEqual(t, w.Dropped(), uint64(7))
+
+	close(out.release)
+	require.NoError(t, w.Close())
+	assert.Equal(t, uint64(10)-w.Dropped(), uint64(strings.Count(out.String(), "line\n")))
+}
+
+func TestLoggerDropped(t *testing.T) {
+	out := &blockingWriter{release: make(chan struct{})}
+	w := NewAsyncWriter(out, WithQueueSize(1), WithBufferSize(1))
+	sink, err := FileSink(FileConfig{Filename: filepath.Join(t.TempDir(), "error.log")}, ErrorLevel)
+	require.NoError(t, err)
+
+	l := NewLogger(WithSinks(Sink{Writer: w}, sink))
+	defer ReplaceGlobal(l)()
+	assert.Zero(t, Dropped())
+
+	for i := 0; i < 10; i++ {
+		l.Info(context.Background()).Msg("line")
+	}
+	assert.Equal(t, w.Dropped(), l.Dropped())
+	assert.GreaterOrEqual(t, Dropped(), uint64(8))
+	assert.Equal(t, l.Dropped(), Named("dropped").Dropped(), "Named 句柄共享 std 的输出")
+
+	close(out.release)
+	require.NoError(t, l.Close())
+}